@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
@@ -16,15 +17,67 @@ type AttemptGetResponse struct {
 	Code         string        `json:"code"`
 	Status       string        `json:"status"`
 	ErrorMessage *string       `json:"error_message"`
-	CreatedAt    time.Time     `json:"created_at"`
-	UpdatedAt    time.Time     `json:"updated_at"`
+	Score        int           `json:"score"`
+	MaxScore     int           `json:"max_score"`
+	// Complexity is the time complexity estimated by the benchmark of the
+	// problem, like O(n log n).
+	Complexity *string `json:"complexity,omitempty"`
+	// CompileDuration is how long the attempt was built, Duration only
+	// counts running it.
+	CompileDuration time.Duration `json:"compile_duration"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+
+	TestResults []AttemptTestResultResponse `json:"test_results,omitempty"`
+}
+
+type AttemptTestResultResponse struct {
+	Index          int             `json:"index"`
+	Subtask        int             `json:"subtask"`
+	Status         string          `json:"status"`
+	Duration       time.Duration   `json:"duration"`
+	MemoryUsage    int64           `json:"memory_usage"`
+	ExitCode       int             `json:"exit_code"`
+	Public         bool            `json:"public"`
+	Input          json.RawMessage `json:"input,omitempty"`
+	ExpectedOutput json.RawMessage `json:"expected_output,omitempty"`
+	ActualOutput   *string         `json:"actual_output,omitempty"`
+	// Stdout is what a function submission printed besides the returned
+	// actual_output, shown on public tests as its own output.
+	Stdout *string `json:"stdout,omitempty"`
+}
+
+type AttemptEventResponse struct {
+	Type         string        `json:"type"`
+	AttemptID    int           `json:"attempt_id"`
+	Stage        string        `json:"stage,omitempty"`
+	Test         int           `json:"test,omitempty"`
+	Total        int           `json:"total,omitempty"`
+	Status       string        `json:"status,omitempty"`
+	Duration     time.Duration `json:"duration,omitempty"`
+	MemoryUsage  int64         `json:"memory_usage,omitempty"`
+	ErrorMessage *string       `json:"error_message,omitempty"`
+	Score        int           `json:"score,omitempty"`
+	MaxScore     int           `json:"max_score,omitempty"`
 }
 
 type AttemptListResponse struct {
 	Attempts []AttemptGetResponse `json:"attempts"`
 }
 
+// AttemptBestScoreResponse is the highest score of the user on a problem.
+type AttemptBestScoreResponse struct {
+	ProblemID int `json:"problem_id"`
+	Score     int `json:"score"`
+	MaxScore  int `json:"max_score"`
+}
+
+type AttemptBestScoresResponse struct {
+	Scores []AttemptBestScoreResponse `json:"scores"`
+}
+
 type AttemptAPI interface {
 	ListByProblemID(fiber.Ctx) error
 	ListByUserID(fiber.Ctx) error
+	BestScores(fiber.Ctx) error
 }
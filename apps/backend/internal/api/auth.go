@@ -35,9 +35,29 @@ type RefreshResponse struct {
 	ExpiresAt    time.Duration `json:"expires_at"`
 }
 
+type SessionResponse struct {
+	ID             int       `json:"id"`
+	UserID         int       `json:"user_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type SessionListResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
+
+type RoleRequest struct {
+	Role string `json:"role"`
+}
+
 type AuthAPI interface {
 	Login(fiber.Ctx) error
 	Register(fiber.Ctx) error
 	Refresh(fiber.Ctx) error
 	Logout(fiber.Ctx) error
+	ListSessions(fiber.Ctx) error
+	RevokeSession(fiber.Ctx) error
+	LogoutUser(fiber.Ctx) error
+	SetRole(fiber.Ctx) error
 }
@@ -16,6 +16,7 @@ type CourseGetResponse struct {
 	Description string    `json:"description"`
 	Tags        []string  `json:"tags"`
 	Status      string    `json:"status"`
+	OwnerID     *int      `json:"owner_id"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	Enrolled    bool      `json:"enrolled"`
@@ -23,8 +24,18 @@ type CourseGetResponse struct {
 	Lessons []LessonGetResponse `json:"lessons,omitempty"`
 }
 
+type CourseRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Status      string   `json:"status"`
+}
+
 type CourseAPI interface {
 	List(fiber.Ctx) error
 	Get(fiber.Ctx) error
 	// Favorite(fiber.Ctx) error
+	Create(fiber.Ctx) error
+	Update(fiber.Ctx) error
+	Delete(fiber.Ctx) error
 }
@@ -19,6 +19,24 @@ type LessonGetResponse struct {
 	Problems []ProblemGetResponse `json:"problems,omitempty"`
 }
 
+// LessonRequest creates or updates a lesson. A zero position appends a new
+// lesson to the end of the course and keeps the position of an existing one.
+type LessonRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Position    int    `json:"position"`
+	Content     string `json:"content"`
+}
+
+// OrderRequest lists the ids of all children of a course or a lesson in their new order.
+type OrderRequest struct {
+	IDs []int `json:"ids"`
+}
+
 type LessonAPI interface {
 	Get(fiber.Ctx) error
+	Create(fiber.Ctx) error
+	Update(fiber.Ctx) error
+	Delete(fiber.Ctx) error
+	Reorder(fiber.Ctx) error
 }
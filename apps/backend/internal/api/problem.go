@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
@@ -14,6 +15,9 @@ type ProblemGetResponse struct {
 	Difficulty  string              `json:"difficulty"`
 	TimeLimit   time.Duration       `json:"time_limit"`
 	MemoryLimit int64               `json:"memory_limit"`
+	Position    int                 `json:"position"`
+	IOMode      string              `json:"io_mode"`
+	Signature   json.RawMessage     `json:"signature,omitempty"`
 	CreatedAt   time.Time           `json:"created_at"`
 	UpdatedAt   time.Time           `json:"updated_at"`
 	Template    TemplateGetResponse `json:"template,omitempty"`
@@ -29,9 +33,86 @@ type ProblemSubmitResponse struct {
 	AttemptID int `json:"attempt_id"`
 }
 
+// ProblemRunRequest runs code on custom inputs. Inputs of stdio problems are
+// JSON strings with the text fed to stdin.
+type ProblemRunRequest struct {
+	Language string            `json:"language"`
+	Code     string            `json:"code"`
+	Inputs   []json.RawMessage `json:"inputs"`
+}
+
+type ProblemRunResponse struct {
+	Status       string                     `json:"status"`
+	ErrorMessage *string                    `json:"error_message"`
+	Outputs      []ProblemRunOutputResponse `json:"outputs"`
+}
+
+// ProblemRunOutputResponse holds the value a function returned in answer, apart
+// from what it printed to stdout. Whole programs answer on stdout.
+type ProblemRunOutputResponse struct {
+	Status       string        `json:"status"`
+	Answer       string        `json:"answer,omitempty"`
+	Stdout       string        `json:"stdout"`
+	Stderr       string        `json:"stderr"`
+	Duration     time.Duration `json:"duration"`
+	MemoryUsage  int64         `json:"memory_usage"`
+	ExitCode     int           `json:"exit_code"`
+	ErrorMessage *string       `json:"error_message"`
+}
+
+// ProblemRequest creates or updates a problem. A zero position appends a new
+// problem to the end of the lesson and keeps the position of an existing one.
+// An empty io_mode means function. A signature like
+//
+//	{"function_name": "twoSum", "parameters": [{"name": "nums", "type": "int[]"}, {"name": "target", "type": "int"}], "return_type": "int[]"}
+//
+// gives a function problem the starter code and harness metadata of every
+// language that can express its types, see package signature.
+type ProblemRequest struct {
+	Name        string          `json:"name"`
+	Statement   string          `json:"statement"`
+	Difficulty  string          `json:"difficulty"`
+	TimeLimit   time.Duration   `json:"time_limit"`
+	MemoryLimit int64           `json:"memory_limit"`
+	Position    int             `json:"position"`
+	IOMode      string          `json:"io_mode"`
+	Signature   json.RawMessage `json:"signature,omitempty"`
+}
+
+// ProblemValidationResponse is the last run of the reference solutions of a
+// problem, judged with the limits given here. A language passes when its
+// reference solution is accepted within the budgets, half of its limits.
+type ProblemValidationResponse struct {
+	ProblemID    int                                 `json:"problem_id"`
+	Passed       bool                                `json:"passed"`
+	ErrorMessage *string                             `json:"error_message,omitempty"`
+	TimeLimit    time.Duration                       `json:"time_limit"`
+	MemoryLimit  int64                               `json:"memory_limit"`
+	Languages    []ProblemValidationLanguageResponse `json:"languages"`
+	ValidatedAt  time.Time                           `json:"validated_at"`
+}
+
+type ProblemValidationLanguageResponse struct {
+	Language     string        `json:"language"`
+	Passed       bool          `json:"passed"`
+	Status       string        `json:"status"`
+	ErrorMessage *string       `json:"error_message,omitempty"`
+	Duration     time.Duration `json:"duration"`
+	MemoryUsage  int64         `json:"memory_usage"`
+	TimeBudget   time.Duration `json:"time_budget"`
+	MemoryBudget int64         `json:"memory_budget"`
+}
+
 type ProblemAPI interface {
 	// надо ли???
 	// List(fiber.Ctx) error
 	Get(fiber.Ctx) error
 	Submit(fiber.Ctx) error
+	Run(fiber.Ctx) error
+	Create(fiber.Ctx) error
+	Update(fiber.Ctx) error
+	Delete(fiber.Ctx) error
+	Reorder(fiber.Ctx) error
+	Validate(fiber.Ctx) error
+	GetValidation(fiber.Ctx) error
 }
@@ -3,6 +3,8 @@ package api
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/gofiber/fiber/v3"
 )
 
 type TemplateGetResponse struct {
@@ -11,6 +13,23 @@ type TemplateGetResponse struct {
 	Language  string          `json:"language"`
 	Code      string          `json:"code"`
 	Metadata  json.RawMessage `json:"metadata"`
+	Reference *string         `json:"reference,omitempty"`
 	CreatedAt time.Time       `json:"created_at"`
 	UpdatedAt time.Time       `json:"updated_at"`
 }
+
+// TemplateRequest creates or updates a template. The language of an existing
+// template can not be changed, so it is ignored on update. Reference is the
+// author's solution in this language, used to generate and validate tests.
+type TemplateRequest struct {
+	Language  string          `json:"language"`
+	Code      string          `json:"code"`
+	Metadata  json.RawMessage `json:"metadata"`
+	Reference *string         `json:"reference,omitempty"`
+}
+
+type TemplateAPI interface {
+	Create(fiber.Ctx) error
+	Update(fiber.Ctx) error
+	Delete(fiber.Ctx) error
+}
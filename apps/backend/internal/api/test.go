@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type TestCase struct {
+	Input   json.RawMessage `json:"input"`
+	Output  json.RawMessage `json:"output"`
+	Public  bool            `json:"public"`
+	Subtask int             `json:"subtask,omitempty"`
+}
+
+type TestSubtask struct {
+	Name   string `json:"name,omitempty"`
+	Points int    `json:"points"`
+}
+
+type TestChecker struct {
+	Mode     string  `json:"mode"`
+	Epsilon  float64 `json:"epsilon,omitempty"`
+	Language string  `json:"language,omitempty"`
+	Code     string  `json:"code,omitempty"`
+}
+
+// TestsRequest replaces all tests of a problem. An empty checker mode means json,
+// whitespace for stdio problems and interactor for interactive ones. Input and
+// output of stdio and interactive tests are JSON strings.
+// Subtask of a test case indexes Subtasks; without subtasks every test is in
+// one group worth 100 points.
+type TestsRequest struct {
+	Tests    []TestCase    `json:"tests"`
+	Checker  TestChecker   `json:"checker"`
+	Subtasks []TestSubtask `json:"subtasks,omitempty"`
+}
+
+type TestsGetResponse struct {
+	ProblemID int           `json:"problem_id"`
+	Tests     []TestCase    `json:"tests"`
+	Checker   TestChecker   `json:"checker"`
+	Subtasks  []TestSubtask `json:"subtasks"`
+	MaxScore  int           `json:"max_score"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// TestGeneratorRequest replaces the generator of a problem. It is written in
+// one of the checker languages and run as `generator <seed>`.
+type TestGeneratorRequest struct {
+	Language string `json:"language"`
+	Code     string `json:"code"`
+}
+
+type TestGeneratorResponse struct {
+	ProblemID int       `json:"problem_id"`
+	Language  string    `json:"language"`
+	Code      string    `json:"code"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TestBenchmarkRequest replaces the benchmark of a problem: inputs of
+// growing size n, held the way tests of the problem hold them, that accepted
+// attempts are timed on to estimate their complexity. MaxComplexity is one of
+// O(1), O(log n), O(n), O(n log n) and O(n^2); attempts estimated above it are
+// not accepted. Sizes should span at least an order of magnitude for the
+// classes to be told apart.
+type TestBenchmarkRequest struct {
+	Inputs        []TestBenchmarkInput `json:"inputs"`
+	MaxComplexity *string              `json:"max_complexity,omitempty"`
+}
+
+type TestBenchmarkInput struct {
+	Size  int             `json:"size"`
+	Input json.RawMessage `json:"input"`
+}
+
+type TestBenchmarkResponse struct {
+	ProblemID     int                  `json:"problem_id"`
+	Inputs        []TestBenchmarkInput `json:"inputs"`
+	MaxComplexity *string              `json:"max_complexity,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// TestsGenerateRequest runs the generator with every seed and answers each
+// input with the reference solution of the template in Language. The tests
+// are added to Subtask, or replace all tests when Replace is set.
+type TestsGenerateRequest struct {
+	Language string `json:"language"`
+	Seeds    []int  `json:"seeds"`
+	Subtask  int    `json:"subtask,omitempty"`
+	Public   bool   `json:"public"`
+	Replace  bool   `json:"replace"`
+}
+
+type TestAPI interface {
+	Get(fiber.Ctx) error
+	Save(fiber.Ctx) error
+	GetGenerator(fiber.Ctx) error
+	SaveGenerator(fiber.Ctx) error
+	GetBenchmark(fiber.Ctx) error
+	SaveBenchmark(fiber.Ctx) error
+	DeleteBenchmark(fiber.Ctx) error
+	Generate(fiber.Ctx) error
+}
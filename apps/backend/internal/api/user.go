@@ -5,5 +5,6 @@ import "time"
 type UserGetResponse struct {
 	ID        int       `json:"id"`
 	Login     string    `json:"login"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
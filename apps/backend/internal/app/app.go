@@ -12,7 +12,9 @@ import (
 
 	"problum/internal/config"
 	"problum/internal/database"
+	"problum/internal/language"
 	"problum/internal/middleware"
+	"problum/internal/model"
 	"problum/internal/nats"
 	"problum/internal/redis"
 	"problum/internal/server"
@@ -50,13 +52,19 @@ import (
 	problemDTO "problum/internal/problem/service/dto"
 
 	attemptHandler "problum/internal/attempt/delivery/http"
+	attemptEvents "problum/internal/attempt/events"
 	attemptRepository "problum/internal/attempt/repository"
 	attemptService "problum/internal/attempt/service"
 	attemptDTO "problum/internal/attempt/service/dto"
 
+	templateHandler "problum/internal/template/delivery/http"
 	templateRepository "problum/internal/template/repository"
 	templateService "problum/internal/template/service"
 
+	testHandler "problum/internal/test/delivery/http"
+	testRepository "problum/internal/test/repository"
+	testService "problum/internal/test/service"
+
 	natsgo "github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/rs/zerolog/log"
@@ -77,6 +85,11 @@ type ProblemService interface {
 	Submit(context.Context, *problemDTO.ProblemSubmit) (int, error)
 }
 
+type CourseService interface {
+	middleware.CourseOwnerService
+	middleware.CourseStatusService
+}
+
 type App struct {
 	httpServer *fiber.App
 	cfg        *config.Config
@@ -129,12 +142,49 @@ func New() (*App, error) {
 
 	attemptRepo := attemptRepository.New(db)
 	attemptSvc := attemptService.New(attemptRepo)
-	attemptHdl := attemptHandler.New(cfg, attemptSvc)
+	eventSubscriber := attemptEvents.NewSubscriber(nc)
+	attemptHdl := attemptHandler.New(cfg, attemptSvc, eventSubscriber)
 
-	templateRepo := templateRepository.New(db)
-	templateSvc := templateService.New(templateRepo)
+	languages, err := language.NewRegistry(cfg.Languages, cfg.Worker.Compile)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create language registry")
+		return nil, fmt.Errorf("failed to create language registry: %w", err)
+	}
+
+	checkers, err := language.NewRegistry(cfg.Checkers, cfg.Worker.Compile)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create checker registry")
+		return nil, fmt.Errorf("failed to create checker registry: %w", err)
+	}
+
+	stdio, err := language.NewRegistry(cfg.Stdio, cfg.Worker.Compile)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create stdio registry")
+		return nil, fmt.Errorf("failed to create stdio registry: %w", err)
+	}
+
+	goTest, err := language.NewRegistry(cfg.GoTest, cfg.Worker.Compile)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create gotest registry")
+		return nil, fmt.Errorf("failed to create gotest registry: %w", err)
+	}
+
+	design, err := language.NewRegistry(cfg.Design, cfg.Worker.Compile)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create design registry")
+		return nil, fmt.Errorf("failed to create design registry: %w", err)
+	}
 
 	problemRepo := problemRepository.New(db)
+
+	templateRepo := templateRepository.New(db)
+	templateSvc := templateService.New(templateRepo, problemRepo, languages, stdio, goTest, design)
+	templateHdl := templateHandler.New(cfg, templateSvc)
+
+	testRepo := testRepository.New(db)
+	testSvc := testService.New(testRepo, problemRepo, checkers, nc)
+	testHdl := testHandler.New(cfg, testSvc)
+
 	problemSvc := problemService.New(problemRepo, js, attemptSvc, templateSvc)
 	problemHdl := problemHandler.New(cfg, problemSvc)
 
@@ -147,7 +197,7 @@ func New() (*App, error) {
 	enrollmentHdl := enrollmentHandler.New(cfg, enrollmentSvc)
 
 	courseRepo := courseRepository.New(db)
-	courseSvc := courseService.New(courseRepo, lessonSvc, enrollmentSvc)
+	courseSvc := courseService.New(courseRepo, lessonSvc, enrollmentSvc, problemSvc)
 	courseHdl := courseHandler.New(cfg, courseSvc)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -182,6 +232,10 @@ func New() (*App, error) {
 		attemptHdl,
 		attemptSvc,
 		userHdl,
+		templateHdl,
+		testHdl,
+		courseSvc,
+		templateSvc,
 	)
 
 	return app, nil
@@ -200,6 +254,10 @@ func setupRoutes(
 	attemptHdl *attemptHandler.Handler,
 	attemptSvc AttemptSvc,
 	userHdl *userHandler.Handler,
+	templateHdl *templateHandler.Handler,
+	testHdl *testHandler.Handler,
+	courseSvc CourseService,
+	templateSvc middleware.TemplateService,
 ) {
 	// healthchecks
 	app.httpServer.Get(healthcheck.LivenessEndpoint, healthcheck.New())
@@ -241,11 +299,14 @@ func setupRoutes(
 	problem.Use(middleware.Course(enrollmentSvc))
 	problem.Get("/:problemID", middleware.Problem(problemSvc, lessonSvc), problemHdl.Get)
 	problem.Post("/:problemID/submit", middleware.Problem(problemSvc, lessonSvc), problemHdl.Submit)
+	problem.Post("/:problemID/run", middleware.Problem(problemSvc, lessonSvc), problemHdl.Run)
 
 	// attempt
+	app.httpServer.Get("/attempts/events", middleware.QueryToken(), middleware.Auth(app.rdb), attemptHdl.Events)
 	attempt := app.httpServer.Group("/attempts")
 	attempt.Use(middleware.Auth(app.rdb))
 	attempt.Get("/", attemptHdl.ListByUserID)
+	attempt.Get("/best", attemptHdl.BestScores)
 	attempt.Get("/:attemptID", middleware.Attempt(attemptSvc), attemptHdl.Get)
 	problem.Get("/:problemID", middleware.Problem(problemSvc, lessonSvc), problemHdl.Get)
 	problem.Get("/:problemID/attempts", middleware.Problem(problemSvc, lessonSvc), attemptHdl.ListByProblemID)
@@ -255,6 +316,51 @@ func setupRoutes(
 	enrollment := app.httpServer.Group("/enrollments")
 	enrollment.Use(middleware.Auth(app.rdb))
 	enrollment.Post("/", enrollmentHdl.Enroll)
+
+	// admin
+	courseOwner := middleware.Owner(courseSvc, middleware.CourseParam())
+	lessonOwner := middleware.Owner(courseSvc, middleware.LessonParam(lessonSvc))
+	problemOwner := middleware.Owner(courseSvc, middleware.ProblemParam(problemSvc, lessonSvc))
+	templateOwner := middleware.Owner(courseSvc, middleware.TemplateParam(templateSvc, problemSvc, lessonSvc))
+
+	// what validation covers is frozen while the course is published
+	lessonDraft := middleware.Draft(courseSvc, middleware.LessonParam(lessonSvc))
+	problemDraft := middleware.Draft(courseSvc, middleware.ProblemParam(problemSvc, lessonSvc))
+	templateDraft := middleware.Draft(courseSvc, middleware.TemplateParam(templateSvc, problemSvc, lessonSvc))
+
+	admin := app.httpServer.Group("/admin")
+	admin.Use(middleware.Auth(app.rdb), middleware.RequireRole(model.RoleAuthor, model.RoleAdmin))
+	admin.Post("/courses", courseHdl.Create)
+	admin.Put("/courses/:courseID", courseOwner, courseHdl.Update)
+	admin.Delete("/courses/:courseID", courseOwner, courseHdl.Delete)
+	admin.Post("/courses/:courseID/lessons", courseOwner, lessonHdl.Create)
+	admin.Put("/courses/:courseID/lessons/order", courseOwner, lessonHdl.Reorder)
+	admin.Put("/lessons/:lessonID", lessonOwner, lessonHdl.Update)
+	admin.Delete("/lessons/:lessonID", lessonOwner, lessonHdl.Delete)
+	admin.Post("/lessons/:lessonID/problems", lessonOwner, lessonDraft, problemHdl.Create)
+	admin.Put("/lessons/:lessonID/problems/order", lessonOwner, problemHdl.Reorder)
+	admin.Put("/problems/:problemID", problemOwner, problemDraft, problemHdl.Update)
+	admin.Delete("/problems/:problemID", problemOwner, problemHdl.Delete)
+	admin.Get("/problems/:problemID/validation", problemOwner, problemHdl.GetValidation)
+	admin.Post("/problems/:problemID/validation", problemOwner, problemHdl.Validate)
+	admin.Get("/problems/:problemID/tests", problemOwner, testHdl.Get)
+	admin.Put("/problems/:problemID/tests", problemOwner, problemDraft, testHdl.Save)
+	admin.Post("/problems/:problemID/tests/generate", problemOwner, problemDraft, testHdl.Generate)
+	admin.Get("/problems/:problemID/generator", problemOwner, testHdl.GetGenerator)
+	admin.Put("/problems/:problemID/generator", problemOwner, testHdl.SaveGenerator)
+	admin.Get("/problems/:problemID/benchmark", problemOwner, testHdl.GetBenchmark)
+	admin.Put("/problems/:problemID/benchmark", problemOwner, problemDraft, testHdl.SaveBenchmark)
+	admin.Delete("/problems/:problemID/benchmark", problemOwner, problemDraft, testHdl.DeleteBenchmark)
+	admin.Post("/problems/:problemID/templates", problemOwner, problemDraft, templateHdl.Create)
+	admin.Put("/templates/:templateID", templateOwner, templateDraft, templateHdl.Update)
+	admin.Delete("/templates/:templateID", templateOwner, templateDraft, templateHdl.Delete)
+
+	users := admin.Group("/users")
+	users.Use(middleware.RequireRole(model.RoleAdmin))
+	users.Put("/:userID/role", authHdl.SetRole)
+	users.Get("/:userID/sessions", authHdl.ListSessions)
+	users.Delete("/:userID/sessions", authHdl.LogoutUser)
+	admin.Delete("/sessions/:sessionID", middleware.RequireRole(model.RoleAdmin), authHdl.RevokeSession)
 }
 
 func (a *App) Run() error {
@@ -1,31 +1,50 @@
 package http
 
 import (
+	"bufio"
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"problum/internal/api"
 	"problum/internal/attempt/service/dto"
 	"problum/internal/config"
 
+	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog/log"
 )
 
+// eventsHeartbeat keeps idle event streams alive and detects gone clients.
+const eventsHeartbeat = 15 * time.Second
+
+// eventsRetry is how long a client waits before it reconnects a dropped stream.
+const eventsRetry = 3 * time.Second
+
 type Service interface {
 	ListByProblemID(context.Context, int, int) ([]*dto.Attempt, error)
 	ListByUserID(context.Context, int) ([]*dto.Attempt, error)
 	Get(context.Context, int) (*dto.Attempt, error)
+	BestScores(context.Context, int, []int) ([]*dto.BestScore, error)
+}
+
+type EventSubscriber interface {
+	Subscribe(userID int) (<-chan *dto.Event, func(), error)
 }
 
 type Handler struct {
-	cfg *config.Config
-	svc Service
+	cfg    *config.Config
+	svc    Service
+	events EventSubscriber
 }
 
-func New(cfg *config.Config, svc Service) *Handler {
+func New(cfg *config.Config, svc Service, events EventSubscriber) *Handler {
 	return &Handler{
-		cfg: cfg,
-		svc: svc,
+		cfg:    cfg,
+		svc:    svc,
+		events: events,
 	}
 }
 
@@ -66,6 +85,37 @@ func (h *Handler) ListByUserID(c fiber.Ctx) error {
 	})
 }
 
+// BestScores returns the user's best score on each problem of the
+// comma-separated problem_ids query, for progress views.
+func (h *Handler) BestScores(c fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.SendStatus(fiber.StatusForbidden)
+	}
+
+	problemIDs := make([]int, 0)
+	for _, raw := range strings.Split(c.Query("problem_ids"), ",") {
+		if raw == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+		problemIDs = append(problemIDs, id)
+	}
+
+	scores, err := h.svc.BestScores(c.Context(), userID, problemIDs)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(api.AttemptBestScoresResponse{
+		Scores: dto.BestScoresToAPI(scores),
+	})
+}
+
 func (h *Handler) Get(c fiber.Ctx) error {
 	attemptID, err := strconv.Atoi(c.Params("attemptID"))
 	if err != nil {
@@ -79,3 +129,63 @@ func (h *Handler) Get(c fiber.Ctx) error {
 
 	return c.JSON(dto.ToAPI(attempt))
 }
+
+// Events streams the progress and verdicts of the user's attempts as server-sent events.
+// Events are not replayed, a client reconnecting refetches its pending attempts.
+func (h *Handler) Events(c fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.SendStatus(fiber.StatusForbidden)
+	}
+
+	events, unsubscribe, err := h.events.Subscribe(userID)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	// fasthttp sets the write deadline of the server once per response, the
+	// stream outlives it, so every write moves it on instead
+	conn := c.RequestCtx().Conn()
+	flush := func(w *bufio.Writer) error {
+		if err := conn.SetWriteDeadline(time.Now().Add(2 * eventsHeartbeat)); err != nil {
+			return err
+		}
+
+		return w.Flush()
+	}
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		heartbeat := time.NewTicker(eventsHeartbeat)
+		defer heartbeat.Stop()
+
+		fmt.Fprintf(w, "retry: %d\n: connected\n\n", eventsRetry.Milliseconds())
+		if err := flush(w); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event := <-events:
+				payload, err := sonic.Marshal(dto.EventToAPI(event))
+				if err != nil {
+					log.Error().Err(err).Msg("Failed to marshal attempt event")
+					continue
+				}
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", time.Now().UnixNano(), event.Type, payload)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+
+			if err := flush(w); err != nil {
+				return
+			}
+		}
+	})
+}
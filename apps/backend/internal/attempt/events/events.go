@@ -0,0 +1,91 @@
+package events
+
+import (
+	"fmt"
+
+	"problum/internal/attempt/service/dto"
+
+	"github.com/bytedance/sonic"
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TypeProgress = "progress"
+	TypeVerdict  = "verdict"
+
+	// subscriberBuffer is how many events a slow subscriber may lag behind before events are dropped.
+	subscriberBuffer = 64
+)
+
+// Subject is the core NATS subject the events of a user are published on. It
+// is deliberately outside of the ATTEMPTS stream: events are only useful live.
+func Subject(userID int) string {
+	return fmt.Sprintf("ATTEMPTS.result.%d", userID)
+}
+
+type Publisher struct {
+	nc *natsgo.Conn
+}
+
+func NewPublisher(nc *natsgo.Conn) *Publisher {
+	return &Publisher{
+		nc: nc,
+	}
+}
+
+func (p *Publisher) Publish(event *dto.Event) error {
+	payload, err := sonic.Marshal(event)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal attempt event")
+		return fmt.Errorf("failed to marshal attempt event: %w", err)
+	}
+
+	if err := p.nc.Publish(Subject(event.UserID), payload); err != nil {
+		log.Error().Err(err).Msg("Failed to publish attempt event")
+		return fmt.Errorf("failed to publish attempt event: %w", err)
+	}
+
+	return nil
+}
+
+type Subscriber struct {
+	nc *natsgo.Conn
+}
+
+func NewSubscriber(nc *natsgo.Conn) *Subscriber {
+	return &Subscriber{
+		nc: nc,
+	}
+}
+
+// Subscribe streams the events of a user until the returned function is called.
+func (s *Subscriber) Subscribe(userID int) (<-chan *dto.Event, func(), error) {
+	ch := make(chan *dto.Event, subscriberBuffer)
+
+	sub, err := s.nc.Subscribe(Subject(userID), func(msg *natsgo.Msg) {
+		event := &dto.Event{}
+		if err := sonic.Unmarshal(msg.Data, event); err != nil {
+			log.Error().Err(err).Msg("Failed to unmarshal attempt event")
+			return
+		}
+
+		select {
+		case ch <- event:
+		default:
+			log.Warn().Int("user_id", userID).Msg("Dropped attempt event for slow subscriber")
+		}
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to subscribe to attempt events")
+		return nil, nil, fmt.Errorf("failed to subscribe to attempt events: %w", err)
+	}
+
+	unsubscribe := func() {
+		if err := sub.Unsubscribe(); err != nil {
+			log.Error().Err(err).Msg("Failed to unsubscribe from attempt events")
+		}
+	}
+
+	return ch, unsubscribe, nil
+}
@@ -32,6 +32,10 @@ func (r *Repository) ListByProblemID(ctx context.Context, userID, problemID int)
 		code,
 		status,
 		error_message,
+		score,
+		max_score,
+		complexity,
+		compile_duration,
 		created_at,
 		updated_at
 	FROM attempts
@@ -59,6 +63,10 @@ func (r *Repository) ListByProblemID(ctx context.Context, userID, problemID int)
 			&attempt.Code,
 			&attempt.Status,
 			&attempt.ErrorMessage,
+			&attempt.Score,
+			&attempt.MaxScore,
+			&attempt.Complexity,
+			&attempt.CompileDuration,
 			&attempt.CreatedAt,
 			&attempt.UpdatedAt,
 		); err != nil {
@@ -88,6 +96,10 @@ func (r *Repository) ListByUserID(ctx context.Context, userID int) ([]*model.Att
 		code,
 		status,
 		error_message,
+		score,
+		max_score,
+		complexity,
+		compile_duration,
 		created_at,
 		updated_at
 	FROM attempts
@@ -115,6 +127,10 @@ func (r *Repository) ListByUserID(ctx context.Context, userID int) ([]*model.Att
 			&attempt.Code,
 			&attempt.Status,
 			&attempt.ErrorMessage,
+			&attempt.Score,
+			&attempt.MaxScore,
+			&attempt.Complexity,
+			&attempt.CompileDuration,
 			&attempt.CreatedAt,
 			&attempt.UpdatedAt,
 		); err != nil {
@@ -176,8 +192,18 @@ func (r *Repository) Submit(ctx context.Context, attempt *model.Attempt) (int, e
 	return id, nil
 }
 
-func (r *Repository) Update(ctx context.Context, attempt *model.Attempt) error {
-	query := `
+// Update updates the attempt and, unless results is nil, replaces its test
+// results in the same transaction, so that a verdict is never stored without
+// the tests behind it.
+func (r *Repository) Update(ctx context.Context, attempt *model.Attempt, results []*model.AttemptTestResult) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin update attempt transaction")
+		return fmt.Errorf("failed to begin update attempt transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	updateQuery := `
 	UPDATE attempts
 	SET
 		duration = $1,
@@ -185,23 +211,99 @@ func (r *Repository) Update(ctx context.Context, attempt *model.Attempt) error {
 		language = $3,
 		code = $4,
 		status = $5,
-		error_message = $6
-	WHERE id = $7
+		error_message = $6,
+		score = $7,
+		max_score = $8,
+		complexity = $9,
+		compile_duration = $10
+	WHERE id = $11
 	`
 
-	if _, err := r.db.Pool.Exec(ctx, query,
+	if _, err := tx.Exec(ctx, updateQuery,
 		attempt.Duration,
 		attempt.MemoryUsage,
 		attempt.Language,
 		attempt.Code,
 		attempt.Status,
 		attempt.ErrorMessage,
+		attempt.Score,
+		attempt.MaxScore,
+		attempt.Complexity,
+		attempt.CompileDuration,
 		attempt.ID,
 	); err != nil {
 		log.Error().Err(err).Msg("Failed to update attempt")
 		return fmt.Errorf("failed to update attempt: %w", err)
 	}
 
+	if results != nil {
+		deleteQuery := `
+		DELETE FROM attempt_test_results
+		WHERE attempt_id = $1
+		`
+
+		if _, err := tx.Exec(ctx, deleteQuery, attempt.ID); err != nil {
+			log.Error().Err(err).Msg("Failed to delete previous test results")
+			return fmt.Errorf("failed to delete previous test results: %w", err)
+		}
+	}
+
+	insertQuery := `
+	INSERT INTO attempt_test_results(
+		attempt_id,
+		test_index,
+		subtask,
+		status,
+		duration,
+		memory_usage,
+		exit_code,
+		public,
+		input,
+		expected_output,
+		actual_output,
+		stdout
+	)
+	VALUES(
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6,
+		$7,
+		$8,
+		$9,
+		$10,
+		$11,
+		$12
+	)
+	`
+
+	for _, result := range results {
+		if _, err := tx.Exec(ctx, insertQuery,
+			attempt.ID,
+			result.TestIndex,
+			result.Subtask,
+			result.Status,
+			result.Duration,
+			result.MemoryUsage,
+			result.ExitCode,
+			result.Public,
+			result.Input,
+			result.ExpectedOutput,
+			result.ActualOutput,
+			result.Stdout,
+		); err != nil {
+			log.Error().Err(err).Msg("Failed to insert test result")
+			return fmt.Errorf("failed to insert test result: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to commit update attempt transaction")
+		return fmt.Errorf("failed to commit update attempt transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -217,6 +319,10 @@ func (r *Repository) Get(ctx context.Context, attemptID int) (*model.Attempt, er
 		code,
 		status,
 		error_message,
+		score,
+		max_score,
+		complexity,
+		compile_duration,
 		created_at,
 		updated_at
 	FROM attempts
@@ -234,6 +340,10 @@ func (r *Repository) Get(ctx context.Context, attemptID int) (*model.Attempt, er
 		&attempt.Code,
 		&attempt.Status,
 		&attempt.ErrorMessage,
+		&attempt.Score,
+		&attempt.MaxScore,
+		&attempt.Complexity,
+		&attempt.CompileDuration,
 		&attempt.CreatedAt,
 		&attempt.UpdatedAt,
 	); err != nil {
@@ -243,3 +353,109 @@ func (r *Repository) Get(ctx context.Context, attemptID int) (*model.Attempt, er
 
 	return attempt, nil
 }
+
+func (r *Repository) ListTestResults(ctx context.Context, attemptID int) ([]*model.AttemptTestResult, error) {
+	query := `
+	SELECT
+		id,
+		attempt_id,
+		test_index,
+		subtask,
+		status,
+		duration,
+		memory_usage,
+		exit_code,
+		public,
+		input,
+		expected_output,
+		actual_output,
+		stdout,
+		created_at,
+		updated_at
+	FROM attempt_test_results
+	WHERE attempt_id = $1
+	ORDER BY test_index
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, attemptID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create test results list query")
+		return nil, fmt.Errorf("failed to create test results list query: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*model.AttemptTestResult, 0)
+	for rows.Next() {
+		result := &model.AttemptTestResult{}
+		if err := rows.Scan(
+			&result.ID,
+			&result.AttemptID,
+			&result.TestIndex,
+			&result.Subtask,
+			&result.Status,
+			&result.Duration,
+			&result.MemoryUsage,
+			&result.ExitCode,
+			&result.Public,
+			&result.Input,
+			&result.ExpectedOutput,
+			&result.ActualOutput,
+			&result.Stdout,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+		); err != nil {
+			log.Error().Err(err).Msg("Failed to scan test result")
+			return nil, fmt.Errorf("failed to scan test result: %w", err)
+		}
+
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to iterate test results")
+		return nil, fmt.Errorf("failed to iterate test results: %w", err)
+	}
+
+	return results, nil
+}
+
+// BestScores returns the highest score of the user on each of the problems
+// they have a judged attempt on.
+func (r *Repository) BestScores(ctx context.Context, userID int, problemIDs []int) ([]*model.BestScore, error) {
+	query := `
+	SELECT DISTINCT ON (problem_id)
+		problem_id,
+		score,
+		max_score
+	FROM attempts
+	WHERE user_id = $1 AND problem_id = ANY($2) AND status <> 'pending'
+	ORDER BY problem_id, score DESC, id DESC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID, problemIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create best scores query")
+		return nil, fmt.Errorf("failed to create best scores query: %w", err)
+	}
+	defer rows.Close()
+
+	scores := make([]*model.BestScore, 0)
+	for rows.Next() {
+		score := &model.BestScore{}
+		if err := rows.Scan(
+			&score.ProblemID,
+			&score.Score,
+			&score.MaxScore,
+		); err != nil {
+			log.Error().Err(err).Msg("Failed to scan best score")
+			return nil, fmt.Errorf("failed to scan best score: %w", err)
+		}
+
+		scores = append(scores, score)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to iterate best scores")
+		return nil, fmt.Errorf("failed to iterate best scores: %w", err)
+	}
+
+	return scores, nil
+}
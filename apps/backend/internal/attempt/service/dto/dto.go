@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"encoding/json"
 	"time"
 
 	"problum/internal/api"
@@ -8,32 +9,78 @@ import (
 )
 
 type Attempt struct {
-	ID           int
-	UserID       int
-	ProblemID    int
-	Duration     time.Duration
-	MemoryUsage  int64
-	Language     string
-	Code         string
-	Status       string
-	ErrorMessage *string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID              int
+	UserID          int
+	ProblemID       int
+	Duration        time.Duration
+	MemoryUsage     int64
+	Language        string
+	Code            string
+	Status          string
+	ErrorMessage    *string
+	Score           int
+	MaxScore        int
+	Complexity      *string
+	CompileDuration time.Duration
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	TestResults     []*TestResult
+}
+
+type TestResult struct {
+	Index          int
+	Subtask        int
+	Status         string
+	Duration       time.Duration
+	MemoryUsage    int64
+	ExitCode       int
+	Public         bool
+	Input          json.RawMessage
+	ExpectedOutput json.RawMessage
+	ActualOutput   *string
+	// Stdout is what a function submission printed besides its answer.
+	Stdout *string
+}
+
+// Event is pushed to the user while their attempt is being judged.
+type Event struct {
+	Type         string        `json:"type"`
+	AttemptID    int           `json:"attempt_id"`
+	UserID       int           `json:"user_id"`
+	Stage        string        `json:"stage,omitempty"`
+	Test         int           `json:"test,omitempty"`
+	Total        int           `json:"total,omitempty"`
+	Status       string        `json:"status,omitempty"`
+	Duration     time.Duration `json:"duration,omitempty"`
+	MemoryUsage  int64         `json:"memory_usage,omitempty"`
+	ErrorMessage *string       `json:"error_message,omitempty"`
+	Score        int           `json:"score,omitempty"`
+	MaxScore     int           `json:"max_score,omitempty"`
+}
+
+type BestScore struct {
+	ProblemID int
+	Score     int
+	MaxScore  int
 }
 
 func ToDTO(attempt *model.Attempt) *Attempt {
 	return &Attempt{
-		ID:           attempt.ID,
-		UserID:       attempt.UserID,
-		ProblemID:    attempt.ProblemID,
-		Duration:     attempt.Duration,
-		MemoryUsage:  attempt.MemoryUsage,
-		Language:     attempt.Language,
-		Code:         attempt.Code,
-		Status:       attempt.Status,
-		ErrorMessage: attempt.ErrorMessage,
-		CreatedAt:    attempt.CreatedAt,
-		UpdatedAt:    attempt.UpdatedAt,
+		ID:              attempt.ID,
+		UserID:          attempt.UserID,
+		ProblemID:       attempt.ProblemID,
+		Duration:        attempt.Duration,
+		MemoryUsage:     attempt.MemoryUsage,
+		Language:        attempt.Language,
+		Code:            attempt.Code,
+		Status:          attempt.Status,
+		ErrorMessage:    attempt.ErrorMessage,
+		Score:           attempt.Score,
+		MaxScore:        attempt.MaxScore,
+		Complexity:      attempt.Complexity,
+		CompileDuration: attempt.CompileDuration,
+		CreatedAt:       attempt.CreatedAt,
+		UpdatedAt:       attempt.UpdatedAt,
 	}
 }
 
@@ -49,33 +96,42 @@ func ToDTOList(attempts []*model.Attempt) []*Attempt {
 
 func ToModel(attempt *Attempt) *model.Attempt {
 	return &model.Attempt{
-		ID:           attempt.ID,
-		UserID:       attempt.UserID,
-		ProblemID:    attempt.ProblemID,
-		Duration:     attempt.Duration,
-		MemoryUsage:  attempt.MemoryUsage,
-		Language:     attempt.Language,
-		Code:         attempt.Code,
-		Status:       attempt.Status,
-		ErrorMessage: attempt.ErrorMessage,
-		CreatedAt:    attempt.CreatedAt,
-		UpdatedAt:    attempt.UpdatedAt,
+		ID:              attempt.ID,
+		UserID:          attempt.UserID,
+		ProblemID:       attempt.ProblemID,
+		Duration:        attempt.Duration,
+		MemoryUsage:     attempt.MemoryUsage,
+		Language:        attempt.Language,
+		Code:            attempt.Code,
+		Status:          attempt.Status,
+		ErrorMessage:    attempt.ErrorMessage,
+		Score:           attempt.Score,
+		MaxScore:        attempt.MaxScore,
+		Complexity:      attempt.Complexity,
+		CompileDuration: attempt.CompileDuration,
+		CreatedAt:       attempt.CreatedAt,
+		UpdatedAt:       attempt.UpdatedAt,
 	}
 }
 
 func ToAPI(attempt *Attempt) api.AttemptGetResponse {
 	return api.AttemptGetResponse{
-		ID:           attempt.ID,
-		UserID:       attempt.UserID,
-		ProblemID:    attempt.ProblemID,
-		Duration:     attempt.Duration,
-		MemoryUsage:  attempt.MemoryUsage,
-		Language:     attempt.Language,
-		Code:         attempt.Code,
-		Status:       attempt.Status,
-		ErrorMessage: attempt.ErrorMessage,
-		CreatedAt:    attempt.CreatedAt,
-		UpdatedAt:    attempt.UpdatedAt,
+		ID:              attempt.ID,
+		UserID:          attempt.UserID,
+		ProblemID:       attempt.ProblemID,
+		Duration:        attempt.Duration,
+		MemoryUsage:     attempt.MemoryUsage,
+		Language:        attempt.Language,
+		Code:            attempt.Code,
+		Status:          attempt.Status,
+		ErrorMessage:    attempt.ErrorMessage,
+		Score:           attempt.Score,
+		MaxScore:        attempt.MaxScore,
+		Complexity:      attempt.Complexity,
+		CompileDuration: attempt.CompileDuration,
+		CreatedAt:       attempt.CreatedAt,
+		UpdatedAt:       attempt.UpdatedAt,
+		TestResults:     TestResultsToAPI(attempt.TestResults),
 	}
 }
 
@@ -88,3 +144,140 @@ func ToAPIList(attempts []*Attempt) []api.AttemptGetResponse {
 
 	return ans
 }
+
+func TestResultToDTO(result *model.AttemptTestResult) *TestResult {
+	return &TestResult{
+		Index:          result.TestIndex,
+		Subtask:        result.Subtask,
+		Status:         result.Status,
+		Duration:       result.Duration,
+		MemoryUsage:    result.MemoryUsage,
+		ExitCode:       result.ExitCode,
+		Public:         result.Public,
+		Input:          result.Input,
+		ExpectedOutput: result.ExpectedOutput,
+		ActualOutput:   result.ActualOutput,
+		Stdout:         result.Stdout,
+	}
+}
+
+func TestResultsToDTO(results []*model.AttemptTestResult) []*TestResult {
+	ans := make([]*TestResult, 0, len(results))
+
+	for _, result := range results {
+		ans = append(ans, TestResultToDTO(result))
+	}
+
+	return ans
+}
+
+func TestResultToModel(attemptID int, result *TestResult) *model.AttemptTestResult {
+	return &model.AttemptTestResult{
+		AttemptID:      attemptID,
+		TestIndex:      result.Index,
+		Subtask:        result.Subtask,
+		Status:         result.Status,
+		Duration:       result.Duration,
+		MemoryUsage:    result.MemoryUsage,
+		ExitCode:       result.ExitCode,
+		Public:         result.Public,
+		Input:          result.Input,
+		ExpectedOutput: result.ExpectedOutput,
+		ActualOutput:   result.ActualOutput,
+		Stdout:         result.Stdout,
+	}
+}
+
+func TestResultsToModel(attemptID int, results []*TestResult) []*model.AttemptTestResult {
+	ans := make([]*model.AttemptTestResult, 0, len(results))
+
+	for _, result := range results {
+		ans = append(ans, TestResultToModel(attemptID, result))
+	}
+
+	return ans
+}
+
+// TestResultToAPI hides everything but the index and verdict for hidden tests.
+func TestResultToAPI(result *TestResult) api.AttemptTestResultResponse {
+	resp := api.AttemptTestResultResponse{
+		Index:       result.Index,
+		Subtask:     result.Subtask,
+		Status:      result.Status,
+		Duration:    result.Duration,
+		MemoryUsage: result.MemoryUsage,
+		ExitCode:    result.ExitCode,
+		Public:      result.Public,
+	}
+
+	if result.Public {
+		resp.Input = result.Input
+		resp.ExpectedOutput = result.ExpectedOutput
+		resp.ActualOutput = result.ActualOutput
+		resp.Stdout = result.Stdout
+	}
+
+	return resp
+}
+
+func TestResultsToAPI(results []*TestResult) []api.AttemptTestResultResponse {
+	if results == nil {
+		return nil
+	}
+
+	ans := make([]api.AttemptTestResultResponse, 0, len(results))
+
+	for _, result := range results {
+		ans = append(ans, TestResultToAPI(result))
+	}
+
+	return ans
+}
+
+func EventToAPI(event *Event) api.AttemptEventResponse {
+	return api.AttemptEventResponse{
+		Type:         event.Type,
+		AttemptID:    event.AttemptID,
+		Stage:        event.Stage,
+		Test:         event.Test,
+		Total:        event.Total,
+		Status:       event.Status,
+		Duration:     event.Duration,
+		MemoryUsage:  event.MemoryUsage,
+		ErrorMessage: event.ErrorMessage,
+		Score:        event.Score,
+		MaxScore:     event.MaxScore,
+	}
+}
+
+func BestScoreToDTO(score *model.BestScore) *BestScore {
+	return &BestScore{
+		ProblemID: score.ProblemID,
+		Score:     score.Score,
+		MaxScore:  score.MaxScore,
+	}
+}
+
+func BestScoresToDTO(scores []*model.BestScore) []*BestScore {
+	ans := make([]*BestScore, 0, len(scores))
+
+	for _, score := range scores {
+		ans = append(ans, BestScoreToDTO(score))
+	}
+
+	return ans
+}
+
+func BestScoresToAPI(scores []*BestScore) []api.AttemptBestScoreResponse {
+	ans := make([]api.AttemptBestScoreResponse, 0, len(scores))
+
+	for _, score := range scores {
+		ans = append(ans, api.AttemptBestScoreResponse{
+			ProblemID: score.ProblemID,
+			Score:     score.Score,
+			MaxScore:  score.MaxScore,
+		})
+	}
+
+	return ans
+}
@@ -14,8 +14,10 @@ type Repository interface {
 	ListByProblemID(context.Context, int, int) ([]*model.Attempt, error)
 	ListByUserID(context.Context, int) ([]*model.Attempt, error)
 	Submit(context.Context, *model.Attempt) (int, error)
-	Update(context.Context, *model.Attempt) error
+	Update(context.Context, *model.Attempt, []*model.AttemptTestResult) error
 	Get(context.Context, int) (*model.Attempt, error)
+	ListTestResults(context.Context, int) ([]*model.AttemptTestResult, error)
+	BestScores(context.Context, int, []int) ([]*model.BestScore, error)
 }
 
 type Service struct {
@@ -58,8 +60,15 @@ func (s *Service) Submit(ctx context.Context, attempt *dto.Attempt) (int, error)
 	return id, nil
 }
 
+// Update stores the attempt together with its test results, the ones stored
+// before are kept when it carries none.
 func (s *Service) Update(ctx context.Context, attempt *dto.Attempt) error {
-	if err := s.repo.Update(ctx, dto.ToModel(attempt)); err != nil {
+	var results []*model.AttemptTestResult
+	if attempt.TestResults != nil {
+		results = dto.TestResultsToModel(attempt.ID, attempt.TestResults)
+	}
+
+	if err := s.repo.Update(ctx, dto.ToModel(attempt), results); err != nil {
 		log.Error().Err(err).Msg("Failed to update attempt")
 		return fmt.Errorf("failed to update attempt: %w", err)
 	}
@@ -74,5 +83,26 @@ func (s *Service) Get(ctx context.Context, attemptID int) (*dto.Attempt, error)
 		return nil, fmt.Errorf("failed to get attempts: %w", err)
 	}
 
-	return dto.ToDTO(attempt), nil
+	results, err := s.repo.ListTestResults(ctx, attemptID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get test results")
+		return nil, fmt.Errorf("failed to get test results: %w", err)
+	}
+
+	attemptDTO := dto.ToDTO(attempt)
+	attemptDTO.TestResults = dto.TestResultsToDTO(results)
+
+	return attemptDTO, nil
+}
+
+// BestScores returns the highest score of the user on each of the problems.
+// Problems without a judged attempt are left out.
+func (s *Service) BestScores(ctx context.Context, userID int, problemIDs []int) ([]*dto.BestScore, error) {
+	scores, err := s.repo.BestScores(ctx, userID, problemIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get best scores")
+		return nil, fmt.Errorf("failed to get best scores: %w", err)
+	}
+
+	return dto.BestScoresToDTO(scores), nil
 }
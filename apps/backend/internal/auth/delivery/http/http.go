@@ -2,13 +2,17 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"problum/internal/api"
+	"problum/internal/auth/service"
 	"problum/internal/auth/service/dto"
 	"problum/internal/config"
+	"problum/internal/model"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/rs/zerolog/log"
@@ -19,6 +23,10 @@ type Service interface {
 	Register(context.Context, string, string, string) (*dto.RegisterDTO, error)
 	Refresh(context.Context, string) (*dto.RefreshDTO, error)
 	Logout(context.Context, string, string) error
+	ListSessions(context.Context, int) ([]*model.UserSession, error)
+	RevokeSession(context.Context, int) error
+	LogoutUser(context.Context, int) error
+	SetRole(context.Context, int, string) error
 }
 
 type Handler struct {
@@ -144,3 +152,88 @@ func (h *Handler) Logout(c fiber.Ctx) error {
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+func (h *Handler) ListSessions(c fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("userID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	sessions, err := h.svc.ListSessions(c.Context(), userID)
+	if err != nil {
+		return h.fail(c, err, "list sessions")
+	}
+
+	resp := api.SessionListResponse{
+		Sessions: make([]api.SessionResponse, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, api.SessionResponse{
+			ID:             session.ID,
+			UserID:         session.UserID,
+			ExpiresAt:      session.ExpiresAt,
+			LastActivityAt: session.LastActivityAt,
+			CreatedAt:      session.CreatedAt,
+		})
+	}
+
+	return c.JSON(resp)
+}
+
+func (h *Handler) RevokeSession(c fiber.Ctx) error {
+	sessionID, err := strconv.Atoi(c.Params("sessionID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if err := h.svc.RevokeSession(c.Context(), sessionID); err != nil {
+		return h.fail(c, err, "revoke session")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) LogoutUser(c fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("userID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if err := h.svc.LogoutUser(c.Context(), userID); err != nil {
+		return h.fail(c, err, "logout user")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) SetRole(c fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("userID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	roleReq := &api.RoleRequest{}
+	if err := c.Bind().JSON(roleReq); err != nil {
+		return err
+	}
+
+	if err := h.svc.SetRole(c.Context(), userID, roleReq.Role); err != nil {
+		return h.fail(c, err, "set role")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// fail maps user management errors to a status code, action is what failed, e.g. "set role".
+func (h *Handler) fail(c fiber.Ctx, err error, action string) error {
+	log.Error().Err(err).Msg("Failed to " + action)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidRole):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrSessionNotFound), errors.Is(err, service.ErrUserNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	default:
+		return fmt.Errorf("failed to %s", action)
+	}
+}
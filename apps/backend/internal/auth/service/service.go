@@ -22,9 +22,17 @@ import (
 
 var HMACRefreshTokenKey = []byte("refresh_token_key")
 
+var (
+	ErrInvalidRole     = errors.New("invalid role")
+	ErrSessionNotFound = sessionRepo.ErrNotFound
+	ErrUserNotFound    = userRepo.ErrNotFound
+)
+
 type UserService interface {
 	FindByLogin(context.Context, string) (*userDTO.User, error)
 	Create(context.Context, *userDTO.User) (*userDTO.User, error)
+	Get(context.Context, int) (*userDTO.User, error)
+	UpdateRole(context.Context, int, string) error
 }
 
 type SessionService interface {
@@ -33,6 +41,8 @@ type SessionService interface {
 	GetByPreviousRefreshHash(context.Context, string) (*model.UserSession, error)
 	Update(context.Context, *model.UserSession) (*model.UserSession, error)
 	LogoutAll(context.Context, int) error
+	Get(context.Context, int) (*model.UserSession, error)
+	ListActiveByUserID(context.Context, int) ([]*model.UserSession, error)
 }
 
 type Service struct {
@@ -112,6 +122,7 @@ func (s *Service) Login(ctx context.Context, login, password string) (*dto.Login
 		log.Error().Err(err).Msg("Failed to create session")
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
+	us.Role = user.Role
 
 	sessionJSON, err := sonic.Marshal(us)
 	if err != nil {
@@ -149,19 +160,7 @@ func (s *Service) Refresh(ctx context.Context, refresh string) (*dto.RefreshDTO,
 				log.Error().Err(e).Int("user_id", us.UserID).Msg("Failed to logout all")
 			}
 
-			accessTokens, e := s.rdb.SMembers(ctx, fmt.Sprintf("user_access_tokens_%d", us.UserID))
-			if e != nil {
-				log.Error().Err(e).Int("user_id", us.UserID).Msg("Failed to get members")
-			} else {
-				for _, access := range accessTokens {
-					if e = s.rdb.Delete(ctx, fmt.Sprintf("user_sessions:%s", access)); e != nil {
-						log.Error().Err(e).Int("user_id", us.UserID).Msg("Failed to delete access token")
-					}
-				}
-			}
-			if e = s.rdb.Delete(ctx, fmt.Sprintf("user_access_tokens_%d", us.UserID)); e != nil {
-				log.Error().Err(e).Int("user_id", us.UserID).Msg("Failed to delete members")
-			}
+			s.dropAccessTokens(ctx, us.UserID, nil)
 
 			return nil, fmt.Errorf("data compromise: %w", err)
 		}
@@ -192,6 +191,14 @@ func (s *Service) Refresh(ctx context.Context, refresh string) (*dto.RefreshDTO,
 		return nil, fmt.Errorf("failed to refresh session: %w", err)
 	}
 
+	// the role may have changed since login, so it is read again on every refresh
+	user, err := s.userSvc.Get(ctx, session.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get user of session")
+		return nil, fmt.Errorf("failed to get user of session: %w", err)
+	}
+	session.Role = user.Role
+
 	sessionJSON, err := sonic.Marshal(session)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to marshal session")
@@ -249,3 +256,97 @@ func (s *Service) Logout(ctx context.Context, access, refresh string) error {
 
 	return nil
 }
+
+// ListSessions returns the active sessions of a user.
+func (s *Service) ListSessions(ctx context.Context, userID int) ([]*model.UserSession, error) {
+	sessions, err := s.sessionSvc.ListActiveByUserID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int("user_id", userID).Msg("Failed to list sessions")
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession logs out a single session of any user.
+func (s *Service) RevokeSession(ctx context.Context, sessionID int) error {
+	session, err := s.sessionSvc.Get(ctx, sessionID)
+	if err != nil {
+		log.Error().Err(err).Int("session_id", sessionID).Msg("Failed to get session")
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	session.Revoked = true
+	if _, err := s.sessionSvc.Update(ctx, session); err != nil {
+		log.Error().Err(err).Int("session_id", sessionID).Msg("Failed to revoke session")
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	s.dropAccessTokens(ctx, session.UserID, func(us *model.UserSession) bool {
+		return us.ID == sessionID
+	})
+
+	return nil
+}
+
+// LogoutUser revokes every session of a user.
+func (s *Service) LogoutUser(ctx context.Context, userID int) error {
+	if err := s.sessionSvc.LogoutAll(ctx, userID); err != nil {
+		log.Error().Err(err).Int("user_id", userID).Msg("Failed to logout all")
+		return fmt.Errorf("failed to logout all: %w", err)
+	}
+
+	s.dropAccessTokens(ctx, userID, nil)
+
+	return nil
+}
+
+// SetRole changes the role of a user. Cached sessions carry the old role, so
+// the access tokens are dropped and the next refresh picks up the new one.
+func (s *Service) SetRole(ctx context.Context, userID int, role string) error {
+	if !model.ValidRole(role) {
+		return fmt.Errorf("%w: %s", ErrInvalidRole, role)
+	}
+
+	if err := s.userSvc.UpdateRole(ctx, userID, role); err != nil {
+		log.Error().Err(err).Int("user_id", userID).Msg("Failed to update role")
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	s.dropAccessTokens(ctx, userID, nil)
+
+	return nil
+}
+
+// dropAccessTokens removes the cached sessions of a user that match, all of them when match is nil.
+func (s *Service) dropAccessTokens(ctx context.Context, userID int, match func(*model.UserSession) bool) {
+	key := fmt.Sprintf("user_access_tokens_%d", userID)
+
+	accessTokens, err := s.rdb.SMembers(ctx, key)
+	if err != nil {
+		log.Error().Err(err).Int("user_id", userID).Msg("Failed to get members")
+		return
+	}
+
+	for _, access := range accessTokens {
+		sessionKey := fmt.Sprintf("user_sessions:%s", access)
+
+		if match != nil {
+			sessionJSON, err := s.rdb.Get(ctx, sessionKey)
+			if err == nil {
+				us := &model.UserSession{}
+				if err := sonic.Unmarshal(sessionJSON, us); err == nil && !match(us) {
+					continue
+				}
+			}
+		}
+
+		if err := s.rdb.Delete(ctx, sessionKey); err != nil {
+			log.Error().Err(err).Int("user_id", userID).Msg("Failed to delete access token")
+		}
+
+		if err := s.rdb.SRem(ctx, key, access); err != nil {
+			log.Error().Err(err).Int("user_id", userID).Msg("Failed to delete member")
+		}
+	}
+}
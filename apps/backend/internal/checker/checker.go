@@ -0,0 +1,263 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+const (
+	ModeExact      = "exact"
+	ModeJSON       = "json"
+	ModeFloat      = "float"
+	ModeUnordered  = "unordered"
+	ModeWhitespace = "whitespace"
+	ModeCustom     = "custom"
+	// ModeInteractor is not a checker: an interactor judges interactive problems while they run.
+	ModeInteractor = "interactor"
+	// ModeGoTest is not a checker either: hidden Go tests judge gotest problems.
+	ModeGoTest = "gotest"
+
+	DefaultEpsilon = 1e-6
+)
+
+// Checker decides whether the output of a submission is an acceptable answer for a test.
+type Checker interface {
+	// Check returns an error only when the checker itself failed, never for a wrong answer.
+	Check(input, expected json.RawMessage, actual []byte) (*Verdict, error)
+}
+
+type Verdict struct {
+	OK      bool
+	Message string
+}
+
+// New returns a built-in checker. Custom checkers and interactors are programs
+// that have to be built and sandboxed, so the solver constructs them itself.
+func New(mode string, epsilon float64) (Checker, error) {
+	switch mode {
+	case ModeExact:
+		return &exactChecker{}, nil
+	case "", ModeJSON:
+		return &jsonChecker{}, nil
+	case ModeFloat:
+		if epsilon <= 0 {
+			epsilon = DefaultEpsilon
+		}
+		return &floatChecker{epsilon: epsilon}, nil
+	case ModeUnordered:
+		return &unorderedChecker{}, nil
+	case ModeWhitespace:
+		return &whitespaceChecker{}, nil
+	default:
+		return nil, fmt.Errorf("unknown checker mode: %s", mode)
+	}
+}
+
+func Accepted() *Verdict {
+	return &Verdict{OK: true}
+}
+
+func WrongAnswer(message string) *Verdict {
+	return &Verdict{Message: message}
+}
+
+// exactChecker compares the raw bytes of the output.
+type exactChecker struct{}
+
+func (c *exactChecker) Check(_, expected json.RawMessage, actual []byte) (*Verdict, error) {
+	if !bytes.Equal(actual, expected) {
+		return WrongAnswer("Wrong answer"), nil
+	}
+
+	return Accepted(), nil
+}
+
+// jsonChecker compares outputs as JSON values, so formatting and key order do not matter.
+type jsonChecker struct{}
+
+func (c *jsonChecker) Check(_, expected json.RawMessage, actual []byte) (*Verdict, error) {
+	return compare(expected, actual, exactNumbers)
+}
+
+// floatChecker is a jsonChecker that accepts numbers within an absolute or relative epsilon.
+type floatChecker struct {
+	epsilon float64
+}
+
+func (c *floatChecker) Check(_, expected json.RawMessage, actual []byte) (*Verdict, error) {
+	return compare(expected, actual, func(want, got json.Number) bool {
+		return closeNumbers(want, got, c.epsilon)
+	})
+}
+
+// unorderedChecker accepts any permutation of the expected top-level list.
+type unorderedChecker struct{}
+
+func (c *unorderedChecker) Check(_, expected json.RawMessage, actual []byte) (*Verdict, error) {
+	want, err := decode(expected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode expected output: %w", err)
+	}
+
+	got, err := decode(actual)
+	if err != nil {
+		return WrongAnswer("Output is not valid JSON"), nil
+	}
+
+	wantItems, ok := want.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected output is not a list")
+	}
+
+	gotItems, ok := got.([]any)
+	if !ok {
+		return WrongAnswer("Output is not a list"), nil
+	}
+
+	if len(wantItems) != len(gotItems) {
+		return WrongAnswer(fmt.Sprintf("Expected %d elements, got %d", len(wantItems), len(gotItems))), nil
+	}
+
+	used := make([]bool, len(gotItems))
+	for _, w := range wantItems {
+		found := false
+		for i, g := range gotItems {
+			if !used[i] && equal(w, g, exactNumbers) {
+				used[i] = true
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return WrongAnswer("Wrong answer"), nil
+		}
+	}
+
+	return Accepted(), nil
+}
+
+// whitespaceChecker compares the output token by token, so neither the kind
+// nor the amount of whitespace between tokens matters.
+type whitespaceChecker struct{}
+
+func (c *whitespaceChecker) Check(_, expected json.RawMessage, actual []byte) (*Verdict, error) {
+	want := strings.Fields(Text(expected))
+	got := strings.Fields(string(actual))
+
+	if len(want) != len(got) {
+		return WrongAnswer(fmt.Sprintf("Expected %d tokens, got %d", len(want), len(got))), nil
+	}
+
+	for i := range want {
+		if want[i] != got[i] {
+			return WrongAnswer(fmt.Sprintf("Wrong answer at token %d", i+1)), nil
+		}
+	}
+
+	return Accepted(), nil
+}
+
+// Text returns the text held by a stdio test input or output: the decoded
+// JSON string, or the raw bytes when it is not a string.
+func Text(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return string(raw)
+	}
+
+	return text
+}
+
+func compare(expected json.RawMessage, actual []byte, numbersEqual func(want, got json.Number) bool) (*Verdict, error) {
+	want, err := decode(expected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode expected output: %w", err)
+	}
+
+	got, err := decode(actual)
+	if err != nil {
+		return WrongAnswer("Output is not valid JSON"), nil
+	}
+
+	if !equal(want, got, numbersEqual) {
+		return WrongAnswer("Wrong answer"), nil
+	}
+
+	return Accepted(), nil
+}
+
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if decoder.More() {
+		return nil, fmt.Errorf("trailing data after JSON value")
+	}
+
+	return v, nil
+}
+
+func equal(want, got any, numbersEqual func(want, got json.Number) bool) bool {
+	switch w := want.(type) {
+	case json.Number:
+		g, ok := got.(json.Number)
+		return ok && numbersEqual(w, g)
+	case []any:
+		g, ok := got.([]any)
+		if !ok || len(w) != len(g) {
+			return false
+		}
+		for i := range w {
+			if !equal(w[i], g[i], numbersEqual) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok || len(w) != len(g) {
+			return false
+		}
+		for key, value := range w {
+			other, ok := g[key]
+			if !ok || !equal(value, other, numbersEqual) {
+				return false
+			}
+		}
+		return true
+	default:
+		return want == got
+	}
+}
+
+// exactNumbers treats 3, 3.0 and 3e0 as the same number.
+func exactNumbers(want, got json.Number) bool {
+	w, ok1 := new(big.Rat).SetString(want.String())
+	g, ok2 := new(big.Rat).SetString(got.String())
+	if !ok1 || !ok2 {
+		return want == got
+	}
+
+	return w.Cmp(g) == 0
+}
+
+func closeNumbers(want, got json.Number, epsilon float64) bool {
+	w, err1 := want.Float64()
+	g, err2 := got.Float64()
+	if err1 != nil || err2 != nil {
+		return exactNumbers(want, got)
+	}
+
+	diff := math.Abs(w - g)
+	return diff <= epsilon || diff <= epsilon*math.Abs(w)
+}
@@ -0,0 +1,113 @@
+package checker
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		epsilon  float64
+		expected string
+		actual   string
+		ok       bool
+	}{
+		{"exact same bytes", ModeExact, 0, `[1,2]`, `[1,2]`, true},
+		{"exact other formatting", ModeExact, 0, `[1,2]`, `[1, 2]`, false},
+
+		{"json formatting and key order", ModeJSON, 0, `{"a":1,"b":[1,2]}`, "{\"b\": [1, 2],\n \"a\": 1}\n", true},
+		{"json default mode", "", 0, `true`, `true`, true},
+		{"json other value", ModeJSON, 0, `{"a":1}`, `{"a":2}`, false},
+		{"json numbers spelled differently", ModeJSON, 0, `[3,0.5]`, `[3.0,5e-1]`, true},
+		{"json big numbers", ModeJSON, 0, `12345678901234567890`, `12345678901234567891`, false},
+		{"json not json", ModeJSON, 0, `1`, `one`, false},
+		{"json trailing data", ModeJSON, 0, `1`, `1 2`, false},
+
+		{"float within epsilon", ModeFloat, 1e-6, `[0.1,2.5]`, `[0.1000001,2.4999999]`, true},
+		{"float outside epsilon", ModeFloat, 1e-6, `0.1`, `0.1001`, false},
+		{"float relative epsilon", ModeFloat, 1e-6, `1000000000`, `1000000100`, true},
+		{"float default epsilon", ModeFloat, 0, `1`, `1.0000000001`, true},
+
+		{"unordered permutation", ModeUnordered, 0, `[[1,2],[3],[1,2]]`, `[[3],[1,2],[1,2]]`, true},
+		{"unordered other multiset", ModeUnordered, 0, `[1,1,2]`, `[1,2,2]`, false},
+		{"unordered other length", ModeUnordered, 0, `[1,2]`, `[1,2,3]`, false},
+		{"unordered not a list", ModeUnordered, 0, `[1]`, `1`, false},
+
+		{"whitespace amount and kind", ModeWhitespace, 0, `"1 2\n3"`, "1\t2   3\n\n", true},
+		{"whitespace other token", ModeWhitespace, 0, `"1 2 3"`, `1 2 4`, false},
+		{"whitespace missing token", ModeWhitespace, 0, `"1 2 3"`, `1 2`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chk, err := New(tt.mode, tt.epsilon)
+			if err != nil {
+				t.Fatalf("New(%q) failed: %v", tt.mode, err)
+			}
+
+			verdict, err := chk.Check(nil, json.RawMessage(tt.expected), []byte(tt.actual))
+			if err != nil {
+				t.Fatalf("Check failed: %v", err)
+			}
+
+			if verdict.OK != tt.ok {
+				t.Errorf("Check(%s, %s) = %v (%q), want %v", tt.expected, tt.actual, verdict.OK, verdict.Message, tt.ok)
+			}
+			if !verdict.OK && verdict.Message == "" {
+				t.Errorf("Check(%s, %s) gave a wrong answer without a message", tt.expected, tt.actual)
+			}
+		})
+	}
+}
+
+func TestCheckInvalidExpected(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		expected string
+	}{
+		{"json", ModeJSON, `{`},
+		{"float", ModeFloat, `{`},
+		{"unordered not a list", ModeUnordered, `{"a":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chk, err := New(tt.mode, 0)
+			if err != nil {
+				t.Fatalf("New(%q) failed: %v", tt.mode, err)
+			}
+
+			if _, err := chk.Check(nil, json.RawMessage(tt.expected), []byte(`[]`)); err == nil {
+				t.Errorf("Check with expected output %s did not fail", tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewUnknownMode(t *testing.T) {
+	for _, mode := range []string{ModeCustom, ModeInteractor, ModeGoTest, "regex"} {
+		if _, err := New(mode, 0); err == nil {
+			t.Errorf("New(%q) did not fail", mode)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{`"1 2\n"`, "1 2\n"},
+		{`[1,2]`, `[1,2]`},
+		{`42`, `42`},
+	}
+
+	for _, tt := range tests {
+		if got := Text(json.RawMessage(tt.raw)); got != tt.want {
+			t.Errorf("Text(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
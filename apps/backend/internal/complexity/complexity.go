@@ -0,0 +1,130 @@
+package complexity
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Classes the timings of a benchmark are fitted against, from the slowest
+// growing one up.
+const (
+	Constant     = "O(1)"
+	Logarithmic  = "O(log n)"
+	Linear       = "O(n)"
+	Linearithmic = "O(n log n)"
+	Quadratic    = "O(n^2)"
+)
+
+// resolution is how precisely isolate measures the time of a run.
+const resolution = time.Millisecond
+
+// noise is the share of the average time runs are allowed to differ by just
+// because the machine is busy.
+const noise = 0.01
+
+type class struct {
+	name   string
+	growth func(n float64) float64
+}
+
+var classes = []class{
+	{Constant, func(float64) float64 { return 1 }},
+	{Logarithmic, func(n float64) float64 { return math.Log2(n) }},
+	{Linear, func(n float64) float64 { return n }},
+	{Linearithmic, func(n float64) float64 { return n * math.Log2(n) }},
+	{Quadratic, func(n float64) float64 { return n * n }},
+}
+
+// Point is how long a run on an input of the size took.
+type Point struct {
+	Size     int
+	Duration time.Duration
+}
+
+// Valid tells whether name is one of the classes.
+func Valid(name string) bool {
+	return rank(name) >= 0
+}
+
+// Exceeds tells whether the class grows faster than the limit.
+func Exceeds(name, limit string) bool {
+	return rank(name) > rank(limit)
+}
+
+func rank(name string) int {
+	return slices.IndexFunc(classes, func(c class) bool { return c.name == name })
+}
+
+// Estimate fits time = a + b·f(size) by least squares for the growth f of
+// every class, the constant a being the start up of the program. It returns
+// the slowest growing class that explains the timings as well as the best
+// fitting one does, give or take the noise of measuring them, so that a
+// solution is never given a worse class than the timings prove.
+func Estimate(points []Point) string {
+	if len(points) < 2 {
+		return Constant
+	}
+
+	ys := make([]float64, len(points))
+	mean := 0.0
+	for i, point := range points {
+		ys[i] = point.Duration.Seconds()
+		mean += ys[i]
+	}
+	mean /= float64(len(points))
+
+	rss := make([]float64, len(classes))
+	for i, c := range classes {
+		xs := make([]float64, len(points))
+		for j, point := range points {
+			xs[j] = c.growth(float64(point.Size))
+		}
+		rss[i] = residuals(xs, ys)
+	}
+
+	sigma := max(resolution.Seconds(), noise*mean)
+	tolerance := slices.Min(rss) + float64(len(points))*sigma*sigma
+
+	for i, c := range classes {
+		if rss[i] <= tolerance {
+			return c.name
+		}
+	}
+
+	return classes[len(classes)-1].name
+}
+
+// residuals is the sum of squared residuals of the least squares line through
+// the points. Time never falls as inputs grow, so a falling line is taken flat.
+func residuals(xs, ys []float64) float64 {
+	n := float64(len(xs))
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var cov, variance float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		variance += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+
+	slope := 0.0
+	if variance > 0 && cov > 0 {
+		slope = cov / variance
+	}
+	intercept := meanY - slope*meanX
+
+	rss := 0.0
+	for i := range xs {
+		d := ys[i] - intercept - slope*xs[i]
+		rss += d * d
+	}
+
+	return rss
+}
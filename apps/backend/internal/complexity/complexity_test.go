@@ -0,0 +1,91 @@
+package complexity
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// timings returns the points a program starting up in startup and spending
+// perUnit on every unit of work the growth gives a size would measure.
+func timings(startup, perUnit time.Duration, growth func(n float64) float64) []Point {
+	sizes := []int{1000, 2000, 4000, 8000, 16000, 32000}
+
+	points := make([]Point, 0, len(sizes))
+	for _, size := range sizes {
+		work := time.Duration(growth(float64(size)) * float64(perUnit))
+		points = append(points, Point{Size: size, Duration: startup + work})
+	}
+
+	return points
+}
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []Point
+		want   string
+	}{
+		{"no points", nil, Constant},
+		{"single point", []Point{{Size: 1000, Duration: time.Second}}, Constant},
+		{"constant", timings(20*time.Millisecond, 0, func(float64) float64 { return 0 }), Constant},
+		{"constant within noise", []Point{
+			{Size: 1000, Duration: 20 * time.Millisecond},
+			{Size: 2000, Duration: 21 * time.Millisecond},
+			{Size: 4000, Duration: 20 * time.Millisecond},
+			{Size: 8000, Duration: 21 * time.Millisecond},
+		}, Constant},
+		{"falling", []Point{
+			{Size: 1000, Duration: 90 * time.Millisecond},
+			{Size: 2000, Duration: 60 * time.Millisecond},
+			{Size: 4000, Duration: 30 * time.Millisecond},
+		}, Constant},
+		{"logarithmic", timings(5*time.Millisecond, 10*time.Millisecond, math.Log2), Logarithmic},
+		{"linear", timings(20*time.Millisecond, 10*time.Microsecond, func(n float64) float64 { return n }), Linear},
+		{"linearithmic", timings(20*time.Millisecond, time.Microsecond, func(n float64) float64 { return n * math.Log2(n) }), Linearithmic},
+		{"quadratic", timings(20*time.Millisecond, time.Nanosecond, func(n float64) float64 { return n * n }), Quadratic},
+		{"cubic", timings(20*time.Millisecond, time.Nanosecond, func(n float64) float64 { return n * n * n / 1000 }), Quadratic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Estimate(tt.points); got != tt.want {
+				t.Errorf("Estimate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExceeds(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit string
+		want  bool
+	}{
+		{Linear, Linear, false},
+		{Logarithmic, Linear, false},
+		{Linearithmic, Linear, true},
+		{Quadratic, Linearithmic, true},
+		{Constant, Constant, false},
+	}
+
+	for _, tt := range tests {
+		if got := Exceeds(tt.name, tt.limit); got != tt.want {
+			t.Errorf("Exceeds(%s, %s) = %v, want %v", tt.name, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, name := range []string{Constant, Logarithmic, Linear, Linearithmic, Quadratic} {
+		if !Valid(name) {
+			t.Errorf("Valid(%s) = false", name)
+		}
+	}
+
+	for _, name := range []string{"", "O(n^3)", "O(2^n)", "linear"} {
+		if Valid(name) {
+			t.Errorf("Valid(%q) = true", name)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"bytes"
+	_ "embed"
 	"fmt"
 	"os"
 	"time"
@@ -37,13 +39,34 @@ const (
 	// nats
 	defaultNatsHost = "0.0.0.0"
 	defaultNatsPort = 4222
+
+	// worker
+	defaultWorkerConcurrency = 1
+	defaultWorkerWorkDir     = "/tmp/problum"
+	defaultWorkerMaxDeliver  = 5
+	defaultWorkerOutputLimit = 64 * 1024 * 1024
+
+	// compile
+	defaultCompileTimeLimit   = time.Duration(30) * time.Second
+	defaultCompileMemoryLimit = 4 * 1024 * 1024 * 1024
+	defaultCompileOutputLimit = 64 * 1024 * 1024
+	defaultCompileUser        = 65534
 )
 
+var defaultWorkerBackoff = []string{"10s", "30s", "1m", "5m"}
+
 type Config struct {
 	Server *Server
 	DB     *DB
 	Redis  *Redis
 	Nats   *Nats
+	Worker *Worker
+
+	Languages map[string]*Language
+	Checkers  map[string]*Language
+	Stdio     map[string]*Language
+	GoTest    map[string]*Language
+	Design    map[string]*Language
 }
 
 type Server struct {
@@ -79,6 +102,61 @@ type Nats struct {
 	Port int    `mapstructure:"port"`
 }
 
+type Worker struct {
+	Concurrency int    `mapstructure:"concurrency"`
+	WorkDir     string `mapstructure:"work_dir"`
+	// MaxDeliver bounds how many times an attempt is judged before it is dead-lettered.
+	MaxDeliver int             `mapstructure:"max_deliver"`
+	Backoff    []time.Duration `mapstructure:"backoff"`
+	// Cgroup makes isolate account memory of the whole process tree in a
+	// control group. It needs cgroup v2 delegated to isolate-cg-keeper.
+	Cgroup bool `mapstructure:"cgroup"`
+	// OutputLimit bounds in bytes every file a submission writes, stdout included.
+	OutputLimit int64 `mapstructure:"output_limit"`
+	// Sandbox is what submissions run in: SandboxIsolate, or SandboxLocal to
+	// run them without root for development and tests.
+	Sandbox string `mapstructure:"sandbox"`
+	Compile *Compile
+}
+
+// Compile bounds the compilers, which run on the worker itself with the
+// code of a submission as their input.
+type Compile struct {
+	TimeLimit time.Duration `mapstructure:"time_limit"`
+	// MemoryLimit bounds the address space of every compiler process.
+	MemoryLimit int64 `mapstructure:"memory_limit"`
+	// OutputLimit bounds in bytes every file a compiler writes.
+	OutputLimit int64 `mapstructure:"output_limit"`
+	// User is the user and group ID compilers run as when the worker runs as
+	// root, so that they can not read what only the worker may.
+	User int `mapstructure:"user"`
+}
+
+const (
+	SandboxIsolate = "isolate"
+	SandboxLocal   = "local"
+)
+
+// Language describes how the solver builds and runs submissions written in a language.
+// Env is the environment of the compiler besides PATH and HOME, Dirs are the
+// directories outside its sources it writes to and Warm is run once the worker
+// starts, so that the caches of the compiler are filled before the first
+// submission needs them.
+type Language struct {
+	Templates        []string `mapstructure:"templates"`
+	Goimports        []string `mapstructure:"goimports"`
+	Compile          []string `mapstructure:"compile"`
+	Env              []string `mapstructure:"env"`
+	Dirs             []string `mapstructure:"dirs"`
+	Warm             []string `mapstructure:"warm"`
+	Artifacts        []string `mapstructure:"artifacts"`
+	Run              []string `mapstructure:"run"`
+	TimeMultiplier   float64  `mapstructure:"time_multiplier"`
+	MemoryMultiplier float64  `mapstructure:"memory_multiplier"`
+	MemoryPadding    int64    `mapstructure:"memory_padding"`
+	Processes        int      `mapstructure:"processes"`
+}
+
 func readServerConfig() *Server {
 	return &Server{
 		Host:            viper.GetString("server.host"),
@@ -120,6 +198,101 @@ func readNatsConfig() *Nats {
 	}
 }
 
+func readWorkerConfig() (*Worker, error) {
+	backoff := make([]time.Duration, 0)
+	for _, value := range viper.GetStringSlice("worker.backoff") {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse worker backoff %q: %w", value, err)
+		}
+		backoff = append(backoff, d)
+	}
+
+	maxDeliver := viper.GetInt("worker.max_deliver")
+	if maxDeliver <= len(backoff) {
+		return nil, fmt.Errorf("worker max_deliver must be greater than the number of backoff steps")
+	}
+
+	sandbox := viper.GetString("worker.sandbox")
+	if sandbox != SandboxIsolate && sandbox != SandboxLocal {
+		return nil, fmt.Errorf("unknown worker sandbox %q", sandbox)
+	}
+
+	// the local sandbox bounds the address space, it has no control groups
+	cgroup := viper.GetBool("worker.cgroup")
+	if cgroup && sandbox == SandboxLocal {
+		return nil, fmt.Errorf("worker cgroup needs the isolate sandbox")
+	}
+
+	return &Worker{
+		Concurrency: viper.GetInt("worker.concurrency"),
+		WorkDir:     viper.GetString("worker.work_dir"),
+		MaxDeliver:  maxDeliver,
+		Backoff:     backoff,
+		Cgroup:      cgroup,
+		OutputLimit: viper.GetInt64("worker.output_limit"),
+		Sandbox:     sandbox,
+		Compile: &Compile{
+			TimeLimit:   viper.GetDuration("worker.compile.time_limit"),
+			MemoryLimit: viper.GetInt64("worker.compile.memory_limit"),
+			OutputLimit: viper.GetInt64("worker.compile.output_limit"),
+			User:        viper.GetInt("worker.compile.user"),
+		},
+	}, nil
+}
+
+func readLanguagesConfig() (map[string]*Language, error) {
+	return readLanguageMap("languages")
+}
+
+// readCheckersConfig reads the languages custom checkers can be written in.
+// A checker is a standalone program, so it is built without the submission harness.
+func readCheckersConfig() (map[string]*Language, error) {
+	return readLanguageMap("checkers")
+}
+
+// readStdioConfig reads how full programs of stdio problems are built. They
+// read the test input themselves, so there is no harness around them.
+func readStdioConfig() (map[string]*Language, error) {
+	return readLanguageMap("stdio")
+}
+
+// readGoTestConfig reads how packages of gotest problems are built together
+// with their hidden tests into a test binary.
+func readGoTestConfig() (map[string]*Language, error) {
+	return readLanguageMap("gotest")
+}
+
+// readDesignConfig reads how classes of design problems are built together
+// with the harness replaying the operations of a test on them.
+func readDesignConfig() (map[string]*Language, error) {
+	return readLanguageMap("design")
+}
+
+// defaultLanguages is where the sections of languages missing from the config
+// file are taken from, so that they are written down once.
+//
+//go:embed languages.yml
+var defaultLanguages []byte
+
+func readLanguageMap(key string) (map[string]*Language, error) {
+	v := viper.GetViper()
+	if !v.IsSet(key) {
+		v = viper.New()
+		v.SetConfigType("yaml")
+		if err := v.ReadConfig(bytes.NewReader(defaultLanguages)); err != nil {
+			return nil, fmt.Errorf("failed to read default languages: %w", err)
+		}
+	}
+
+	languages := make(map[string]*Language)
+	if err := v.UnmarshalKey(key, &languages); err != nil {
+		return nil, fmt.Errorf("failed to read %s config: %w", key, err)
+	}
+
+	return languages, nil
+}
+
 func setDefault() {
 	// server
 	viper.SetDefault("server.host", defaultServerHost)
@@ -148,6 +321,18 @@ func setDefault() {
 	// nats
 	viper.SetDefault("nats.host", defaultNatsHost)
 	viper.SetDefault("nats.port", defaultNatsPort)
+
+	// worker
+	viper.SetDefault("worker.concurrency", defaultWorkerConcurrency)
+	viper.SetDefault("worker.work_dir", defaultWorkerWorkDir)
+	viper.SetDefault("worker.max_deliver", defaultWorkerMaxDeliver)
+	viper.SetDefault("worker.backoff", defaultWorkerBackoff)
+	viper.SetDefault("worker.output_limit", defaultWorkerOutputLimit)
+	viper.SetDefault("worker.sandbox", SandboxIsolate)
+	viper.SetDefault("worker.compile.time_limit", defaultCompileTimeLimit)
+	viper.SetDefault("worker.compile.memory_limit", defaultCompileMemoryLimit)
+	viper.SetDefault("worker.compile.output_limit", defaultCompileOutputLimit)
+	viper.SetDefault("worker.compile.user", defaultCompileUser)
 }
 
 func (c *DB) GetDSN() string {
@@ -186,10 +371,53 @@ func New() (*Config, error) {
 	redisConfig := readRedisConfig()
 	natsConifg := readNatsConfig()
 
+	workerConfig, err := readWorkerConfig()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to read worker config")
+		return nil, err
+	}
+
+	languagesConfig, err := readLanguagesConfig()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to read languages config")
+		return nil, err
+	}
+
+	checkersConfig, err := readCheckersConfig()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to read checkers config")
+		return nil, err
+	}
+
+	stdioConfig, err := readStdioConfig()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to read stdio config")
+		return nil, err
+	}
+
+	goTestConfig, err := readGoTestConfig()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to read gotest config")
+		return nil, err
+	}
+
+	designConfig, err := readDesignConfig()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to read design config")
+		return nil, err
+	}
+
 	return &Config{
 		Server: serverConfig,
 		DB:     dbConfig,
 		Redis:  redisConfig,
 		Nats:   natsConifg,
+		Worker: workerConfig,
+
+		Languages: languagesConfig,
+		Checkers:  checkersConfig,
+		Stdio:     stdioConfig,
+		GoTest:    goTestConfig,
+		Design:    designConfig,
 	}, nil
 }
@@ -2,18 +2,25 @@ package http
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
 
 	"problum/internal/api"
 	"problum/internal/config"
+	"problum/internal/course/service"
 	"problum/internal/course/service/dto"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog/log"
 )
 
 type Service interface {
 	List(context.Context) ([]*dto.CourseDTO, error)
 	Get(context.Context, int) (*dto.CourseDTO, error)
+	Create(context.Context, *dto.CourseDTO) (*dto.CourseDTO, error)
+	Update(context.Context, *dto.CourseDTO) (*dto.CourseDTO, error)
+	Delete(context.Context, int) error
 }
 
 type Handler struct {
@@ -52,3 +59,86 @@ func (h *Handler) Get(c fiber.Ctx) error {
 
 	return c.JSON(dto.ToAPI(resp))
 }
+
+func (h *Handler) Create(c fiber.Ctx) error {
+	courseReq := &api.CourseRequest{}
+	if err := c.Bind().JSON(courseReq); err != nil {
+		return err
+	}
+
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	course := requestToDTO(0, courseReq)
+	course.OwnerID = &userID
+
+	course, err := h.svc.Create(c.Context(), course)
+	if err != nil {
+		return h.fail(c, err, "create course")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToAPI(course))
+}
+
+func (h *Handler) Update(c fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("courseID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	courseReq := &api.CourseRequest{}
+	if err := c.Bind().JSON(courseReq); err != nil {
+		return err
+	}
+
+	course, err := h.svc.Update(c.Context(), requestToDTO(id, courseReq))
+	if err != nil {
+		return h.fail(c, err, "update course")
+	}
+
+	return c.JSON(dto.ToAPI(course))
+}
+
+func (h *Handler) Delete(c fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("courseID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if err := h.svc.Delete(c.Context(), id); err != nil {
+		return h.fail(c, err, "delete course")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// fail maps authoring errors to a status code, action is what failed, e.g. "create course".
+func (h *Handler) fail(c fiber.Ctx, err error, action string) error {
+	log.Error().Err(err).Msg("Failed to " + action)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidCourse):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	default:
+		return fmt.Errorf("failed to %s", action)
+	}
+}
+
+func requestToDTO(id int, req *api.CourseRequest) *dto.CourseDTO {
+	tags := req.Tags
+	if tags == nil {
+		tags = make([]string, 0)
+	}
+
+	return &dto.CourseDTO{
+		ID:          id,
+		Name:        req.Name,
+		Description: req.Description,
+		Tags:        tags,
+		Status:      req.Status,
+	}
+}
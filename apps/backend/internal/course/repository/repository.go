@@ -8,6 +8,7 @@ import (
 	"problum/internal/database"
 	"problum/internal/model"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog/log"
 )
 
@@ -31,6 +32,7 @@ func (r *Repository) List(ctx context.Context) ([]*model.Course, error) {
 		description,
 		tags,
 		status,
+		owner_id,
 		created_at,
 		updated_at
 	FROM courses
@@ -52,6 +54,7 @@ func (r *Repository) List(ctx context.Context) ([]*model.Course, error) {
 			&course.Description,
 			&course.Tags,
 			&course.Status,
+			&course.OwnerID,
 			&course.CreatedAt,
 			&course.UpdatedAt,
 		); err != nil {
@@ -77,6 +80,7 @@ func (r *Repository) Get(ctx context.Context, id int) (*model.Course, error) {
 		description,
 		tags,
 		status,
+		owner_id,
 		created_at,
 		updated_at
 	FROM courses
@@ -90,12 +94,112 @@ func (r *Repository) Get(ctx context.Context, id int) (*model.Course, error) {
 		&course.Description,
 		&course.Tags,
 		&course.Status,
+		&course.OwnerID,
 		&course.CreatedAt,
 		&course.UpdatedAt,
 	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
 		log.Error().Err(err).Msg("Failed to get course")
 		return nil, fmt.Errorf("failed to get course: %w", err)
 	}
 
 	return course, nil
 }
+
+func (r *Repository) Create(ctx context.Context, course *model.Course) (*model.Course, error) {
+	query := `
+	INSERT INTO courses (name, description, tags, status, owner_id)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING
+		id,
+		name,
+		description,
+		tags,
+		status,
+		owner_id,
+		created_at,
+		updated_at
+	`
+
+	c := &model.Course{}
+	if err := r.db.Pool.QueryRow(ctx, query, course.Name, course.Description, course.Tags, course.Status, course.OwnerID).Scan(
+		&c.ID,
+		&c.Name,
+		&c.Description,
+		&c.Tags,
+		&c.Status,
+		&c.OwnerID,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	); err != nil {
+		log.Error().Err(err).Msg("Failed to create course")
+		return nil, fmt.Errorf("failed to create course: %w", err)
+	}
+
+	return c, nil
+}
+
+func (r *Repository) Update(ctx context.Context, course *model.Course) (*model.Course, error) {
+	query := `
+	UPDATE courses
+	SET
+		name = $2,
+		description = $3,
+		tags = $4,
+		status = $5,
+		updated_at = NOW()
+	WHERE id = $1
+	RETURNING
+		id,
+		name,
+		description,
+		tags,
+		status,
+		owner_id,
+		created_at,
+		updated_at
+	`
+
+	c := &model.Course{}
+	if err := r.db.Pool.QueryRow(ctx, query, course.ID, course.Name, course.Description, course.Tags, course.Status).Scan(
+		&c.ID,
+		&c.Name,
+		&c.Description,
+		&c.Tags,
+		&c.Status,
+		&c.OwnerID,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to update course")
+		return nil, fmt.Errorf("failed to update course: %w", err)
+	}
+
+	return c, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	query := `
+	DELETE FROM courses
+	WHERE id = $1
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete course")
+		return fmt.Errorf("failed to delete course: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
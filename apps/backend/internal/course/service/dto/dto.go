@@ -16,6 +16,7 @@ func ToDTO(course *model.Course, lessons []*lessonDTO.Lesson, enrolled bool) *Co
 		Description: course.Description,
 		Tags:        course.Tags,
 		Status:      course.Status,
+		OwnerID:     course.OwnerID,
 		CreatedAt:   course.CreatedAt,
 		UpdatedAt:   course.UpdatedAt,
 		Lessons:     lessons,
@@ -23,6 +24,19 @@ func ToDTO(course *model.Course, lessons []*lessonDTO.Lesson, enrolled bool) *Co
 	}
 }
 
+func ToModel(course *CourseDTO) *model.Course {
+	return &model.Course{
+		ID:          course.ID,
+		Name:        course.Name,
+		Description: course.Description,
+		Tags:        course.Tags,
+		Status:      course.Status,
+		OwnerID:     course.OwnerID,
+		CreatedAt:   course.CreatedAt,
+		UpdatedAt:   course.UpdatedAt,
+	}
+}
+
 func ToDTOList(courses []*model.Course, enrollments []*enrollmentDTO.Enrollment) []*CourseDTO {
 	ans := make([]*CourseDTO, 0, len(courses))
 
@@ -45,6 +59,7 @@ func ToAPI(course *CourseDTO) api.CourseGetResponse {
 		Description: course.Description,
 		Tags:        course.Tags,
 		Status:      course.Status,
+		OwnerID:     course.OwnerID,
 		CreatedAt:   course.CreatedAt,
 		UpdatedAt:   course.UpdatedAt,
 		Lessons:     lessonDTO.ToAPIList(course.Lessons),
@@ -68,6 +83,7 @@ type CourseDTO struct {
 	Description string
 	Tags        []string
 	Status      string
+	OwnerID     *int
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Enrolled    bool
@@ -2,8 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
+	"problum/internal/course/repository"
 	"problum/internal/course/service/dto"
 	"problum/internal/model"
 
@@ -13,9 +18,21 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+var (
+	ErrInvalidCourse = errors.New("invalid course")
+	ErrNotFound      = repository.ErrNotFound
+)
+
+const statusPublished = "published"
+
+var statuses = []string{"draft", statusPublished}
+
 type Repository interface {
 	List(context.Context) ([]*model.Course, error)
 	Get(context.Context, int) (*model.Course, error)
+	Create(context.Context, *model.Course) (*model.Course, error)
+	Update(context.Context, *model.Course) (*model.Course, error)
+	Delete(context.Context, int) error
 }
 
 type LessonService interface {
@@ -27,17 +44,23 @@ type EnrollmentService interface {
 	GetListByUserID(context.Context, int) ([]*enrollmentDTO.Enrollment, error)
 }
 
+type ProblemService interface {
+	ListUnvalidatedIDsByCourseID(context.Context, int) ([]int, error)
+}
+
 type Service struct {
 	repo          Repository
 	lessonSvc     LessonService
 	enrollmentSvc EnrollmentService
+	problemSvc    ProblemService
 }
 
-func New(repo Repository, lessonSvc LessonService, enrollmentSvc EnrollmentService) *Service {
+func New(repo Repository, lessonSvc LessonService, enrollmentSvc EnrollmentService, problemSvc ProblemService) *Service {
 	return &Service{
 		repo:          repo,
 		lessonSvc:     lessonSvc,
 		enrollmentSvc: enrollmentSvc,
+		problemSvc:    problemSvc,
 	}
 }
 
@@ -87,3 +110,114 @@ func (s *Service) Get(ctx context.Context, id int) (*dto.CourseDTO, error) {
 
 	return dto.ToDTO(course, lessons, enrolled), nil
 }
+
+// GetOwnerID returns the author who owns the course, nil if nobody does.
+func (s *Service) GetOwnerID(ctx context.Context, id int) (*int, error) {
+	course, err := s.repo.Get(ctx, id)
+	if err != nil {
+		log.Error().Int("course_id", id).Err(err).Msg("Failed to get course")
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+
+	return course.OwnerID, nil
+}
+
+// IsPublished tells whether the course is open to students, the judging of its
+// problems stays as it was validated until it is a draft again.
+func (s *Service) IsPublished(ctx context.Context, id int) (bool, error) {
+	course, err := s.repo.Get(ctx, id)
+	if err != nil {
+		log.Error().Int("course_id", id).Err(err).Msg("Failed to get course")
+		return false, fmt.Errorf("failed to get course: %w", err)
+	}
+
+	return course.Status == statusPublished, nil
+}
+
+func (s *Service) Create(ctx context.Context, course *dto.CourseDTO) (*dto.CourseDTO, error) {
+	if err := validate(course); err != nil {
+		return nil, err
+	}
+
+	// a new course has no validated problems yet, it is published by an update
+	if course.Status == statusPublished {
+		return nil, fmt.Errorf("%w: a course is created as a draft", ErrInvalidCourse)
+	}
+
+	created, err := s.repo.Create(ctx, dto.ToModel(course))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create course")
+		return nil, fmt.Errorf("failed to create course: %w", err)
+	}
+
+	return dto.ToDTO(created, nil, false), nil
+}
+
+func (s *Service) Update(ctx context.Context, course *dto.CourseDTO) (*dto.CourseDTO, error) {
+	if err := validate(course); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.Get(ctx, course.ID)
+	if err != nil {
+		log.Error().Int("course_id", course.ID).Err(err).Msg("Failed to get course")
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+
+	// a published course stays editable, only publishing requires validated problems
+	if course.Status == statusPublished && current.Status != statusPublished {
+		if err := s.checkPublishable(ctx, course.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.Update(ctx, dto.ToModel(course))
+	if err != nil {
+		log.Error().Int("course_id", course.ID).Err(err).Msg("Failed to update course")
+		return nil, fmt.Errorf("failed to update course: %w", err)
+	}
+
+	return dto.ToDTO(updated, nil, false), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		log.Error().Int("course_id", id).Err(err).Msg("Failed to delete course")
+		return fmt.Errorf("failed to delete course: %w", err)
+	}
+
+	return nil
+}
+
+// checkPublishable fails while any problem of the course has no passed and
+// current validation of its reference solutions.
+func (s *Service) checkPublishable(ctx context.Context, id int) error {
+	ids, err := s.problemSvc.ListUnvalidatedIDsByCourseID(ctx, id)
+	if err != nil {
+		log.Error().Int("course_id", id).Err(err).Msg("Failed to list unvalidated problems")
+		return fmt.Errorf("failed to list unvalidated problems: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	problems := make([]string, 0, len(ids))
+	for _, problemID := range ids {
+		problems = append(problems, strconv.Itoa(problemID))
+	}
+
+	return fmt.Errorf("%w: problems %s have not passed validation", ErrInvalidCourse, strings.Join(problems, ", "))
+}
+
+func validate(course *dto.CourseDTO) error {
+	if strings.TrimSpace(course.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCourse)
+	}
+
+	if !slices.Contains(statuses, course.Status) {
+		return fmt.Errorf("%w: status must be one of %s", ErrInvalidCourse, strings.Join(statuses, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package language
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxCompileOutput bounds what is kept of the output of a compiler, the rest
+// is dropped as it comes.
+const maxCompileOutput = 64 * 1024
+
+// command is how every compiler of the language is run: in dir with the
+// environment of the language only, in a process group of its own killed when
+// ctx is done and, when the worker is root, as the compile user. limited puts
+// it under the rlimits of the compile config, warming is trusted and is not.
+func (l *language) command(ctx context.Context, dir string, limited bool, argv []string) *exec.Cmd {
+	if limited {
+		// POSIX shells take one limit at a time and count the file size in
+		// blocks of 512 bytes
+		limits := []string{
+			"ulimit -t " + strconv.Itoa(int(max(l.compile.TimeLimit.Seconds(), 1))),
+			"ulimit -v " + strconv.FormatInt(l.compile.MemoryLimit/1024, 10),
+			"ulimit -f " + strconv.FormatInt(max(l.compile.OutputLimit/512, 1), 10),
+		}
+		argv = append([]string{"/bin/sh", "-c", strings.Join(limits, " && ") + ` && exec "$@"`, "sh"}, argv...)
+	}
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = dir
+	cmd.Env = append([]string{"PATH=" + os.Getenv("PATH"), "HOME=" + dir}, l.cfg.Env...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if uid, ok := l.compileUser(); ok {
+		cmd.SysProcAttr.Credential = &syscall.Credential{Uid: uid, Gid: uid}
+	}
+
+	// the compiler runs whatever it needs in its group, all of it goes
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+
+	return cmd
+}
+
+// compileUser is the user compilers are run as, only root can switch to it.
+func (l *language) compileUser() (uint32, bool) {
+	if l.compile.User <= 0 || os.Geteuid() != 0 {
+		return 0, false
+	}
+
+	return uint32(l.compile.User), true
+}
+
+// chown gives the tree at path to the compile user, leaving what it has already.
+func (l *language) chown(path string) error {
+	uid, ok := l.compileUser()
+	if !ok {
+		return nil
+	}
+
+	return filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Uid == uid && stat.Gid == uid {
+			return nil
+		}
+
+		return os.Lchown(path, int(uid), int(uid))
+	})
+}
+
+// buildDir copies the regular files of dir into a scratch dir of their own,
+// which the compiler gets instead of the workspace.
+func (l *language) buildDir(dir string) (string, error) {
+	buildDir, err := os.MkdirTemp("", "compile-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create build dir: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		os.RemoveAll(buildDir)
+		return "", fmt.Errorf("failed to read sources: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		if err := copyFile(filepath.Join(dir, entry.Name()), filepath.Join(buildDir, entry.Name())); err != nil {
+			os.RemoveAll(buildDir)
+			return "", err
+		}
+	}
+
+	if err := l.chown(buildDir); err != nil {
+		os.RemoveAll(buildDir)
+		return "", fmt.Errorf("failed to give build dir to compile user: %w", err)
+	}
+
+	return buildDir, nil
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(src), err)
+	}
+
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(dst), err)
+	}
+
+	return nil
+}
+
+// limitedBuffer keeps the first maxCompileOutput bytes written to it and
+// drops the rest, so that a compiler can not flood the worker.
+type limitedBuffer struct {
+	data      []byte
+	truncated bool
+}
+
+var _ io.Writer = (*limitedBuffer)(nil)
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := maxCompileOutput - len(b.data); room < len(p) {
+		b.data = append(b.data, p[:max(room, 0)]...)
+		b.truncated = true
+	} else {
+		b.data = append(b.data, p...)
+	}
+
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return string(b.data) + "\n... (truncated)"
+	}
+
+	return string(b.data)
+}
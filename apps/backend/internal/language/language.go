@@ -0,0 +1,231 @@
+package language
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"problum/internal/config"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/tools/imports"
+)
+
+// Language is a runtime the solver knows how to build and run.
+type Language interface {
+	Name() string
+	// Templates are rendered into the scratch dir before compilation.
+	Templates() []string
+	// Compile builds the rendered sources inside dir. Languages without a
+	// compile step return nil. A *CompileError means the submission is broken.
+	// The compiler works on a copy of the sources under the limits of the
+	// compile config, only the artifacts are copied back.
+	Compile(ctx context.Context, dir string) error
+	// Warm fills the caches compiles share, like the Go build cache, so that
+	// the first submission does not pay for it. Languages without it return nil,
+	// as do the ones sharing a cache warmed already.
+	Warm(ctx context.Context) error
+	// Artifacts are the files copied from the scratch dir into the isolate box.
+	Artifacts() []string
+	RunCommand() []string
+	TimeLimit(time.Duration) time.Duration
+	// MemoryLimit returns the limit in bytes the submission is judged against.
+	MemoryLimit(int64) int64
+	// SandboxMemory returns the address space limit in bytes given to isolate
+	// when it runs without a control group.
+	SandboxMemory(int64) int64
+	Processes() int
+}
+
+type CompileError struct {
+	Output string
+}
+
+func (e *CompileError) Error() string {
+	return "compile error: " + e.Output
+}
+
+type language struct {
+	name    string
+	cfg     *config.Language
+	compile *config.Compile
+}
+
+func New(name string, cfg *config.Language, compile *config.Compile) Language {
+	return &language{
+		name:    name,
+		cfg:     cfg,
+		compile: compile,
+	}
+}
+
+func (l *language) Name() string {
+	return l.name
+}
+
+func (l *language) Templates() []string {
+	return l.cfg.Templates
+}
+
+func (l *language) Compile(ctx context.Context, dir string) error {
+	for _, filename := range l.cfg.Goimports {
+		if err := goimports(filepath.Join(dir, filename)); err != nil {
+			return err
+		}
+	}
+
+	if len(l.cfg.Compile) == 0 {
+		return nil
+	}
+
+	buildDir, err := l.buildDir(dir)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(buildDir)
+
+	compileCtx, cancel := context.WithTimeout(ctx, l.compile.TimeLimit)
+	defer cancel()
+
+	compileCmd := l.command(compileCtx, buildDir, true, l.cfg.Compile)
+
+	var compileStdout limitedBuffer
+	var compileStderr limitedBuffer
+	compileCmd.Stdout = &compileStdout
+	compileCmd.Stderr = &compileStderr
+
+	if err := compileCmd.Run(); err != nil {
+		log.Error().
+			Str("language", l.name).
+			Str("stdout", compileStdout.String()).
+			Str("stderr", compileStderr.String()).
+			Err(err).
+			Msg("Failed to compile")
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to run compiler: %w", ctx.Err())
+		}
+		if compileCtx.Err() != nil {
+			return &CompileError{Output: "Compilation time limit exceeded"}
+		}
+
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("failed to run compiler: %w", err)
+		}
+
+		output := compileStderr.String()
+		if output == "" {
+			output = compileStdout.String()
+		}
+
+		return &CompileError{Output: output}
+	}
+
+	for _, artifact := range l.cfg.Artifacts {
+		if err := copyFile(filepath.Join(buildDir, artifact), filepath.Join(dir, artifact)); err != nil {
+			return fmt.Errorf("failed to copy artifact: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// warmed holds a sync.Once for every cache the languages warm, keyed by its
+// dirs and the warm command. The Go languages of every registry share one
+// build cache, which is warmed once per process.
+var warmed sync.Map
+
+func (l *language) Warm(ctx context.Context) error {
+	if len(l.cfg.Warm) == 0 {
+		return nil
+	}
+
+	key := strings.Join(l.cfg.Dirs, "\x00") + "\x00" + strings.Join(l.cfg.Warm, "\x00")
+	once, _ := warmed.LoadOrStore(key, &sync.Once{})
+
+	var err error
+	once.(*sync.Once).Do(func() {
+		err = l.warm(ctx)
+	})
+
+	return err
+}
+
+func (l *language) warm(ctx context.Context) error {
+	for _, dir := range l.cfg.Dirs {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+
+		if err := l.chown(dir); err != nil {
+			return fmt.Errorf("failed to give %s to compile user: %w", dir, err)
+		}
+	}
+
+	warmCmd := l.command(ctx, os.TempDir(), false, l.cfg.Warm)
+
+	if output, err := warmCmd.CombinedOutput(); err != nil {
+		log.Error().Str("language", l.name).Str("output", string(output)).Err(err).Msg("Failed to warm")
+		return fmt.Errorf("failed to warm %s: %w", l.name, err)
+	}
+
+	return nil
+}
+
+func (l *language) Artifacts() []string {
+	return l.cfg.Artifacts
+}
+
+func (l *language) RunCommand() []string {
+	return l.cfg.Run
+}
+
+func (l *language) TimeLimit(limit time.Duration) time.Duration {
+	return time.Duration(float64(limit) * multiplier(l.cfg.TimeMultiplier))
+}
+
+func (l *language) MemoryLimit(limit int64) int64 {
+	return int64(float64(limit) * multiplier(l.cfg.MemoryMultiplier))
+}
+
+func (l *language) SandboxMemory(limit int64) int64 {
+	return l.cfg.MemoryPadding + l.MemoryLimit(limit)
+}
+
+func (l *language) Processes() int {
+	return max(l.cfg.Processes, 1)
+}
+
+func goimports(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	formatted, err := imports.Process(path, src, nil)
+	if err != nil {
+		log.Error().Err(err).Str("file", filepath.Base(path)).Msg("Failed to format file")
+		return &CompileError{Output: err.Error()}
+	}
+
+	if err := os.WriteFile(path, formatted, 0o644); err != nil {
+		return fmt.Errorf("failed to write formatted %s: %w", filepath.Base(path), err)
+	}
+
+	return nil
+}
+
+func multiplier(m float64) float64 {
+	if m <= 0 {
+		return 1
+	}
+
+	return m
+}
@@ -0,0 +1,87 @@
+package language
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"problum/internal/config"
+)
+
+type Registry struct {
+	mu        sync.RWMutex
+	languages map[string]Language
+}
+
+func NewRegistry(cfg map[string]*config.Language, compile *config.Compile) (*Registry, error) {
+	r := &Registry{
+		languages: make(map[string]Language, len(cfg)),
+	}
+
+	for name, langCfg := range cfg {
+		if langCfg == nil || len(langCfg.Run) == 0 {
+			return nil, fmt.Errorf("language %q has no run command", name)
+		}
+
+		r.Register(New(name, langCfg, compile))
+	}
+
+	return r, nil
+}
+
+func (r *Registry) Register(lang Language) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.languages[lang.Name()] = lang
+}
+
+func (r *Registry) Get(name string) (Language, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lang, ok := r.languages[name]
+	return lang, ok
+}
+
+func (r *Registry) Has(name string) bool {
+	_, ok := r.Get(name)
+	return ok
+}
+
+// Warm warms every registered language. A language failing to warm only
+// leaves its first compiles slower, so the others are warmed regardless.
+func (r *Registry) Warm(ctx context.Context) error {
+	var errs []error
+	for _, name := range r.Names() {
+		lang, _ := r.Get(name)
+		if err := lang.Warm(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.languages))
+	for name := range r.languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Filter keeps only the registered languages, preserving order.
+func (r *Registry) Filter(languages []string) []string {
+	return slices.DeleteFunc(slices.Clone(languages), func(name string) bool {
+		return !r.Has(name)
+	})
+}
@@ -2,10 +2,13 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 
+	"problum/internal/api"
 	"problum/internal/config"
+	"problum/internal/lesson/service"
 	"problum/internal/lesson/service/dto"
 
 	"github.com/gofiber/fiber/v3"
@@ -17,6 +20,10 @@ type Service interface {
 	// пока не нужны для апишки
 	// List(ctx context.Context) ([]*dto.Lesson, error)
 	// ListByCourseID(ctx context.Context, id int) ([]*dto.Lesson, error)
+	Create(context.Context, *dto.Lesson) (*dto.Lesson, error)
+	Update(context.Context, *dto.Lesson) (*dto.Lesson, error)
+	Delete(context.Context, int) error
+	Reorder(context.Context, int, []int) error
 }
 
 type Handler struct {
@@ -46,3 +53,98 @@ func (h *Handler) Get(c fiber.Ctx) error {
 
 	return c.JSON(dto.ToAPI(lesson))
 }
+
+func (h *Handler) Create(c fiber.Ctx) error {
+	courseID, err := strconv.Atoi(c.Params("courseID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	lessonReq := &api.LessonRequest{}
+	if err := c.Bind().JSON(lessonReq); err != nil {
+		return err
+	}
+
+	lesson, err := h.svc.Create(c.Context(), &dto.Lesson{
+		CourseID:    courseID,
+		Name:        lessonReq.Name,
+		Description: lessonReq.Description,
+		Position:    lessonReq.Position,
+		Content:     lessonReq.Content,
+	})
+	if err != nil {
+		return h.fail(c, err, "create lesson")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToAPI(lesson))
+}
+
+func (h *Handler) Update(c fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("lessonID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	lessonReq := &api.LessonRequest{}
+	if err := c.Bind().JSON(lessonReq); err != nil {
+		return err
+	}
+
+	lesson, err := h.svc.Update(c.Context(), &dto.Lesson{
+		ID:          id,
+		Name:        lessonReq.Name,
+		Description: lessonReq.Description,
+		Position:    lessonReq.Position,
+		Content:     lessonReq.Content,
+	})
+	if err != nil {
+		return h.fail(c, err, "update lesson")
+	}
+
+	return c.JSON(dto.ToAPI(lesson))
+}
+
+func (h *Handler) Delete(c fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("lessonID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if err := h.svc.Delete(c.Context(), id); err != nil {
+		return h.fail(c, err, "delete lesson")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) Reorder(c fiber.Ctx) error {
+	courseID, err := strconv.Atoi(c.Params("courseID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	orderReq := &api.OrderRequest{}
+	if err := c.Bind().JSON(orderReq); err != nil {
+		return err
+	}
+
+	if err := h.svc.Reorder(c.Context(), courseID, orderReq.IDs); err != nil {
+		return h.fail(c, err, "reorder lessons")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// fail maps authoring errors to a status code, action is what failed, e.g. "create lesson".
+func (h *Handler) fail(c fiber.Ctx, err error, action string) error {
+	log.Error().Err(err).Msg("Failed to " + action)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidLesson):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	default:
+		return fmt.Errorf("failed to %s", action)
+	}
+}
@@ -2,14 +2,22 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"problum/internal/database"
 	"problum/internal/model"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog/log"
 )
 
+var (
+	ErrNotFound     = errors.New("lesson not found")
+	ErrInvalidOrder = errors.New("order must list every lesson of the course exactly once")
+)
+
 type Repository struct {
 	db *database.DB
 }
@@ -114,6 +122,7 @@ func (r *Repository) ListByCourseID(ctx context.Context, id int) ([]*model.Lesso
 		updated_at
 	FROM lessons
 	WHERE course_id = $1
+	ORDER BY position, id
 	`
 
 	rows, err := r.db.Pool.Query(ctx, query, id)
@@ -149,3 +158,188 @@ func (r *Repository) ListByCourseID(ctx context.Context, id int) ([]*model.Lesso
 
 	return lessons, nil
 }
+
+// Create appends the lesson to the end of the course unless a position is given.
+func (r *Repository) Create(ctx context.Context, lesson *model.Lesson) (*model.Lesson, error) {
+	query := `
+	INSERT INTO lessons (course_id, name, description, position, content)
+	SELECT
+		courses.id,
+		$2,
+		$3,
+		COALESCE(NULLIF($4, 0), (SELECT COALESCE(MAX(position), 0) + 1 FROM lessons WHERE course_id = courses.id)),
+		$5
+	FROM courses
+	WHERE courses.id = $1
+	RETURNING
+		id,
+		course_id,
+		name,
+		description,
+		position,
+		content,
+		created_at,
+		updated_at
+	`
+
+	l := &model.Lesson{}
+	if err := r.db.Pool.QueryRow(ctx, query,
+		lesson.CourseID,
+		lesson.Name,
+		lesson.Description,
+		lesson.Position,
+		lesson.Content,
+	).Scan(
+		&l.ID,
+		&l.CourseID,
+		&l.Name,
+		&l.Description,
+		&l.Position,
+		&l.Content,
+		&l.CreatedAt,
+		&l.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to create lesson")
+		return nil, fmt.Errorf("failed to create lesson: %w", err)
+	}
+
+	return l, nil
+}
+
+func (r *Repository) Update(ctx context.Context, lesson *model.Lesson) (*model.Lesson, error) {
+	query := `
+	UPDATE lessons
+	SET
+		name = $2,
+		description = $3,
+		position = COALESCE(NULLIF($4, 0), position),
+		content = $5,
+		updated_at = NOW()
+	WHERE id = $1
+	RETURNING
+		id,
+		course_id,
+		name,
+		description,
+		position,
+		content,
+		created_at,
+		updated_at
+	`
+
+	l := &model.Lesson{}
+	if err := r.db.Pool.QueryRow(ctx, query,
+		lesson.ID,
+		lesson.Name,
+		lesson.Description,
+		lesson.Position,
+		lesson.Content,
+	).Scan(
+		&l.ID,
+		&l.CourseID,
+		&l.Name,
+		&l.Description,
+		&l.Position,
+		&l.Content,
+		&l.CreatedAt,
+		&l.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to update lesson")
+		return nil, fmt.Errorf("failed to update lesson: %w", err)
+	}
+
+	return l, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	query := `
+	DELETE FROM lessons
+	WHERE id = $1
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete lesson")
+		return fmt.Errorf("failed to delete lesson: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Reorder numbers the lessons of a course from 1 in the order of ids.
+func (r *Repository) Reorder(ctx context.Context, courseID int, ids []int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin reorder lessons transaction")
+		return fmt.Errorf("failed to begin reorder lessons transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	selectQuery := `
+	SELECT id
+	FROM lessons
+	WHERE course_id = $1
+	FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, selectQuery, courseID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to lock lessons of the course")
+		return fmt.Errorf("failed to lock lessons of the course: %w", err)
+	}
+
+	current, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to collect lessons of the course")
+		return fmt.Errorf("failed to collect lessons of the course: %w", err)
+	}
+
+	if !sameIDs(current, ids) {
+		return ErrInvalidOrder
+	}
+
+	updateQuery := `
+	UPDATE lessons
+	SET
+		position = ordered.position,
+		updated_at = NOW()
+	FROM unnest($2::int[]) WITH ORDINALITY AS ordered(id, position)
+	WHERE lessons.id = ordered.id AND lessons.course_id = $1
+	`
+
+	if _, err := tx.Exec(ctx, updateQuery, courseID, ids); err != nil {
+		log.Error().Err(err).Msg("Failed to reorder lessons")
+		return fmt.Errorf("failed to reorder lessons: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to commit reorder lessons transaction")
+		return fmt.Errorf("failed to commit reorder lessons transaction: %w", err)
+	}
+
+	return nil
+}
+
+func sameIDs(current, ids []int) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	slices.Sort(current)
+
+	return slices.Equal(current, sorted)
+}
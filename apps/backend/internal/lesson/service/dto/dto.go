@@ -47,6 +47,19 @@ func ToDTO(lesson *model.Lesson, problems []*problemDTO.Problem) *Lesson {
 	}
 }
 
+func ToModel(lesson *Lesson) *model.Lesson {
+	return &model.Lesson{
+		ID:          lesson.ID,
+		CourseID:    lesson.CourseID,
+		Name:        lesson.Name,
+		Description: lesson.Description,
+		Position:    lesson.Position,
+		Content:     lesson.Content,
+		CreatedAt:   lesson.CreatedAt,
+		UpdatedAt:   lesson.UpdatedAt,
+	}
+}
+
 func ToDTOList(lessons []*model.Lesson, m map[int][]*problemDTO.Problem) []*Lesson {
 	ans := make([]*Lesson, 0, len(lessons))
 
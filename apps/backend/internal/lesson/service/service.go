@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
+	"problum/internal/lesson/repository"
 	"problum/internal/lesson/service/dto"
 	"problum/internal/model"
 	problemDTO "problum/internal/problem/service/dto"
@@ -12,10 +15,19 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+var (
+	ErrInvalidLesson = errors.New("invalid lesson")
+	ErrNotFound      = repository.ErrNotFound
+)
+
 type Repository interface {
 	Get(context.Context, int) (*model.Lesson, error)
 	List(context.Context) ([]*model.Lesson, error)
 	ListByCourseID(context.Context, int) ([]*model.Lesson, error)
+	Create(context.Context, *model.Lesson) (*model.Lesson, error)
+	Update(context.Context, *model.Lesson) (*model.Lesson, error)
+	Delete(context.Context, int) error
+	Reorder(context.Context, int, []int) error
 }
 
 type ProblemService interface {
@@ -90,3 +102,69 @@ func (s *Service) ListByCourseID(ctx context.Context, id int) ([]*dto.Lesson, er
 
 	return dto.ToDTOList(lessons, m), nil
 }
+
+func (s *Service) Create(ctx context.Context, lesson *dto.Lesson) (*dto.Lesson, error) {
+	if err := validate(lesson); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(ctx, dto.ToModel(lesson))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create lesson")
+		return nil, fmt.Errorf("failed to create lesson: %w", err)
+	}
+
+	return dto.ToDTO(created, nil), nil
+}
+
+func (s *Service) Update(ctx context.Context, lesson *dto.Lesson) (*dto.Lesson, error) {
+	if err := validate(lesson); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.Update(ctx, dto.ToModel(lesson))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update lesson")
+		return nil, fmt.Errorf("failed to update lesson: %w", err)
+	}
+
+	return dto.ToDTO(updated, nil), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		log.Error().Err(err).Msg("Failed to delete lesson")
+		return fmt.Errorf("failed to delete lesson: %w", err)
+	}
+
+	return nil
+}
+
+// Reorder takes the ids of all lessons of the course in their new order.
+func (s *Service) Reorder(ctx context.Context, courseID int, ids []int) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: order is empty", ErrInvalidLesson)
+	}
+
+	if err := s.repo.Reorder(ctx, courseID, ids); err != nil {
+		log.Error().Err(err).Msg("Failed to reorder lessons")
+		if errors.Is(err, repository.ErrInvalidOrder) {
+			return fmt.Errorf("%w: %w", ErrInvalidLesson, err)
+		}
+		return fmt.Errorf("failed to reorder lessons: %w", err)
+	}
+
+	return nil
+}
+
+func validate(lesson *dto.Lesson) error {
+	if strings.TrimSpace(lesson.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidLesson)
+	}
+
+	if lesson.Position < 0 {
+		return fmt.Errorf("%w: position must not be negative", ErrInvalidLesson)
+	}
+
+	return nil
+}
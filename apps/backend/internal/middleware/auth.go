@@ -39,3 +39,17 @@ func Auth(rdb *redis.Redis) fiber.Handler {
 		return c.Next()
 	}
 }
+
+// QueryToken lets clients that can not set headers, such as EventSource, pass
+// the access token in the access_token query parameter. It must run before Auth.
+func QueryToken() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if c.Get("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request().Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"problum/internal/model"
+	templateDTO "problum/internal/template/service/dto"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type CourseOwnerService interface {
+	GetOwnerID(context.Context, int) (*int, error)
+}
+
+type CourseStatusService interface {
+	IsPublished(context.Context, int) (bool, error)
+}
+
+type TemplateService interface {
+	Get(context.Context, int) (*templateDTO.Template, error)
+}
+
+// CourseResolver finds the course a request is about.
+type CourseResolver func(fiber.Ctx) (int, error)
+
+// RequireRole lets through only users whose session has one of the roles. It must run after Auth.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		us, ok := c.Locals("user_session").(*model.UserSession)
+		if !ok {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		if !slices.Contains(roles, us.Role) {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+
+		return c.Next()
+	}
+}
+
+// Owner lets through users who may manage any content and authors of the
+// course resolved from the request.
+func Owner(courseSvc CourseOwnerService, resolve CourseResolver) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		us, ok := c.Locals("user_session").(*model.UserSession)
+		if !ok {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		if model.HasPermission(us.Role, model.PermissionManageContent) {
+			return c.Next()
+		}
+
+		if !model.HasPermission(us.Role, model.PermissionAuthorContent) {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+
+		courseID, err := resolve(c)
+		if err != nil {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+
+		ownerID, err := courseSvc.GetOwnerID(c.Context(), courseID)
+		if err != nil {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+
+		if ownerID == nil || *ownerID != us.UserID {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+
+		return c.Next()
+	}
+}
+
+// Draft lets through changes to how the problems of the course resolved from
+// the request are judged only while the course is a draft, a published one was
+// validated as it is. It must run after Owner.
+func Draft(courseSvc CourseStatusService, resolve CourseResolver) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		courseID, err := resolve(c)
+		if err != nil {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+
+		published, err := courseSvc.IsPublished(c.Context(), courseID)
+		if err != nil {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+
+		if published {
+			return c.Status(fiber.StatusConflict).SendString("the course is published, turn it back into a draft to change its problems")
+		}
+
+		return c.Next()
+	}
+}
+
+func CourseParam() CourseResolver {
+	return func(c fiber.Ctx) (int, error) {
+		return strconv.Atoi(c.Params("courseID"))
+	}
+}
+
+func LessonParam(lessonSvc LessonService) CourseResolver {
+	return func(c fiber.Ctx) (int, error) {
+		lessonID, err := strconv.Atoi(c.Params("lessonID"))
+		if err != nil {
+			return 0, err
+		}
+
+		return courseOfLesson(c.Context(), lessonSvc, lessonID)
+	}
+}
+
+func ProblemParam(problemSvc ProblemService, lessonSvc LessonService) CourseResolver {
+	return func(c fiber.Ctx) (int, error) {
+		problemID, err := strconv.Atoi(c.Params("problemID"))
+		if err != nil {
+			return 0, err
+		}
+
+		return courseOfProblem(c.Context(), problemSvc, lessonSvc, problemID)
+	}
+}
+
+func TemplateParam(templateSvc TemplateService, problemSvc ProblemService, lessonSvc LessonService) CourseResolver {
+	return func(c fiber.Ctx) (int, error) {
+		templateID, err := strconv.Atoi(c.Params("templateID"))
+		if err != nil {
+			return 0, err
+		}
+
+		template, err := templateSvc.Get(c.Context(), templateID)
+		if err != nil {
+			return 0, err
+		}
+
+		return courseOfProblem(c.Context(), problemSvc, lessonSvc, template.ProblemID)
+	}
+}
+
+func courseOfProblem(ctx context.Context, problemSvc ProblemService, lessonSvc LessonService, problemID int) (int, error) {
+	problem, err := problemSvc.GetWithOptions(ctx, problemID)
+	if err != nil {
+		return 0, err
+	}
+
+	return courseOfLesson(ctx, lessonSvc, problem.LessonID)
+}
+
+func courseOfLesson(ctx context.Context, lessonSvc LessonService, lessonID int) (int, error) {
+	lesson, err := lessonSvc.Get(ctx, lessonID)
+	if err != nil {
+		return 0, err
+	}
+
+	return lesson.CourseID, nil
+}
@@ -13,6 +13,10 @@ CREATE TABLE IF NOT EXISTS attempts (
     code TEXT,
     status TEXT,
     error_message TEXT NULL,
+    score INTEGER NOT NULL DEFAULT 0,
+    max_score INTEGER NOT NULL DEFAULT 0,
+    complexity TEXT NULL,
+    compile_duration INTERVAL NOT NULL DEFAULT '0',
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW()
 );
@@ -28,6 +32,19 @@ type Attempt struct {
 	Code         string        `db:"code"`
 	Status       string        `db:"status"`
 	ErrorMessage *string       `db:"error_message"`
-	CreatedAt    time.Time     `db:"created_at"`
-	UpdatedAt    time.Time     `db:"updated_at"`
+	Score        int           `db:"score"`
+	MaxScore     int           `db:"max_score"`
+	Complexity   *string       `db:"complexity"`
+	// CompileDuration is how long building the attempt took, apart from
+	// Duration spent running it.
+	CompileDuration time.Duration `db:"compile_duration"`
+	CreatedAt       time.Time     `db:"created_at"`
+	UpdatedAt       time.Time     `db:"updated_at"`
+}
+
+// BestScore is the highest score a user has got on a problem.
+type BestScore struct {
+	ProblemID int `db:"problem_id"`
+	Score     int `db:"score"`
+	MaxScore  int `db:"max_score"`
 }
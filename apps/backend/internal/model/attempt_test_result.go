@@ -0,0 +1,45 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/*
+CREATE TABLE IF NOT EXISTS attempt_test_results (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    attempt_id INTEGER NOT NULL REFERENCES attempts(id) ON DELETE CASCADE,
+    test_index INTEGER NOT NULL,
+    subtask INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL,
+    duration INTERVAL,
+    memory_usage BIGINT,
+    exit_code INTEGER,
+    public BOOLEAN DEFAULT false,
+    input JSONB NULL,
+    expected_output JSONB NULL,
+    actual_output TEXT NULL,
+    stdout TEXT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE(attempt_id, test_index)
+);
+*/
+
+type AttemptTestResult struct {
+	ID             int             `db:"id"`
+	AttemptID      int             `db:"attempt_id"`
+	TestIndex      int             `db:"test_index"`
+	Subtask        int             `db:"subtask"`
+	Status         string          `db:"status"`
+	Duration       time.Duration   `db:"duration"`
+	MemoryUsage    int64           `db:"memory_usage"`
+	ExitCode       int             `db:"exit_code"`
+	Public         bool            `db:"public"`
+	Input          json.RawMessage `db:"input"`
+	ExpectedOutput json.RawMessage `db:"expected_output"`
+	ActualOutput   *string         `db:"actual_output"`
+	Stdout         *string         `db:"stdout"`
+	CreatedAt      time.Time       `db:"created_at"`
+	UpdatedAt      time.Time       `db:"updated_at"`
+}
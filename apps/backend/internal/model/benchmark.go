@@ -0,0 +1,26 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/*
+CREATE TABLE IF NOT EXISTS benchmarks (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    problem_id INTEGER NOT NULL UNIQUE REFERENCES problems(id) ON DELETE CASCADE,
+    inputs JSONB NOT NULL,
+    max_complexity TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+)
+*/
+
+type Benchmark struct {
+	ID            int             `db:"id"`
+	ProblemID     int             `db:"problem_id"`
+	Inputs        json.RawMessage `db:"inputs"`
+	MaxComplexity *string         `db:"max_complexity"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
+}
@@ -9,6 +9,7 @@ CREATE TABLE IF NOT EXISTS courses (
     description TEXT,
     tags TEXT [],
     status TEXT,
+    owner_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW()
 );
@@ -20,6 +21,7 @@ type Course struct {
 	Description string    `db:"description"`
 	Tags        []string  `db:"tags"`
 	Status      string    `db:"status"`
+	OwnerID     *int      `db:"owner_id"`
 	CreatedAt   time.Time `db:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at"`
 }
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+/*
+CREATE TABLE IF NOT EXISTS generators (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    problem_id INTEGER NOT NULL UNIQUE REFERENCES problems(id) ON DELETE CASCADE,
+    language TEXT NOT NULL,
+    code TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+)
+*/
+
+type Generator struct {
+	ID        int       `db:"id"`
+	ProblemID int       `db:"problem_id"`
+	Language  string    `db:"language"`
+	Code      string    `db:"code"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 /*
 CREATE TABLE IF NOT EXISTS problems (
@@ -11,11 +14,34 @@ CREATE TABLE IF NOT EXISTS problems (
     difficulty TEXT,
     time_limit INTERVAL,
     memory_limit BIGINT,
+    position INTEGER,
+    io_mode TEXT NOT NULL DEFAULT 'function' CHECK (io_mode IN ('function', 'stdio', 'interactive', 'gotest', 'design')),
+    signature JSONB,
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW()
 );
 */
 
+// IOModeFunction problems call the function_name of the template with the
+// JSON test input, IOModeStdio ones run the whole program on raw stdin,
+// IOModeInteractive ones run it against an interactor of the problem author,
+// IOModeGoTest ones build a Go package with hidden tests of the author and
+// IOModeDesign ones replay the operations of the JSON test input on an
+// instance of the class_name of the template.
+const (
+	IOModeFunction    = "function"
+	IOModeStdio       = "stdio"
+	IOModeInteractive = "interactive"
+	IOModeGoTest      = "gotest"
+	IOModeDesign      = "design"
+)
+
+// Harnessed tells whether submissions of the io mode are wrapped in a harness
+// reading the JSON test input and writing a JSON answer.
+func Harnessed(ioMode string) bool {
+	return ioMode == IOModeFunction || ioMode == IOModeDesign
+}
+
 type Problem struct {
 	ID          int           `db:"id"`
 	LessonID    int           `db:"lesson_id"`
@@ -24,6 +50,11 @@ type Problem struct {
 	Difficulty  string        `db:"difficulty"`
 	TimeLimit   time.Duration `db:"time_limit"`
 	MemoryLimit int64         `db:"memory_limit"`
-	CreatedAt   time.Time     `db:"created_at"`
-	UpdatedAt   time.Time     `db:"updated_at"`
+	Position    int           `db:"position"`
+	IOMode      string        `db:"io_mode"`
+	// Signature is the function of a function problem for all languages at
+	// once, null when each template carries its own metadata.
+	Signature json.RawMessage `db:"signature"`
+	CreatedAt time.Time       `db:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at"`
 }
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/*
+CREATE TABLE IF NOT EXISTS problem_validations (
+    problem_id INTEGER PRIMARY KEY REFERENCES problems(id) ON DELETE CASCADE,
+    passed BOOLEAN NOT NULL,
+    time_limit INTERVAL NOT NULL,
+    memory_limit BIGINT NOT NULL,
+    report JSONB NOT NULL DEFAULT '{}'::jsonb,
+    validated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+*/
+
+// ProblemValidation is the last run of the reference solutions of a problem.
+// The limits are those it was validated with.
+type ProblemValidation struct {
+	ProblemID   int             `db:"problem_id"`
+	Passed      bool            `db:"passed"`
+	TimeLimit   time.Duration   `db:"time_limit"`
+	MemoryLimit int64           `db:"memory_limit"`
+	Report      json.RawMessage `db:"report"`
+	ValidatedAt time.Time       `db:"validated_at"`
+}
@@ -32,4 +32,7 @@ type UserSession struct {
 	LastActivityAt time.Time `db:"last_activity_at"`
 	CreatedAt      time.Time `db:"created_at"`
 	UpdatedAt      time.Time `db:"updated_at"`
+
+	// Role is not stored with the session, it is copied from the user into the cached session.
+	Role string `db:"-"`
 }
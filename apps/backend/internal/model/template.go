@@ -12,6 +12,7 @@ CREATE TABLE IF NOT EXISTS templates (
     language TEXT,
     code TEXT,
 	metadata JSONB,
+    reference TEXT,
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW()
 );
@@ -23,6 +24,7 @@ type Template struct {
 	Language  string          `db:"language"`
 	Code      string          `db:"code"`
 	Metadata  json.RawMessage `db:"metadata"`
+	Reference *string         `db:"reference"`
 	CreatedAt time.Time       `db:"created_at"`
 	UpdatedAt time.Time       `db:"updated_at"`
 }
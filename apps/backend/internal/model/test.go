@@ -10,6 +10,8 @@ CREATE TABLE IF NOT EXISTS tests (
     id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
     problem_id INTEGER NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
     tests JSONB,
+    checker JSONB NOT NULL DEFAULT '{"mode": "json"}'::jsonb,
+    subtasks JSONB NOT NULL DEFAULT '[]'::jsonb,
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW()
 )
@@ -19,6 +21,8 @@ type Test struct {
 	ID        int             `db:"id"`
 	ProblemID int             `db:"problem_id"`
 	Tests     json.RawMessage `db:"tests"`
+	Checker   json.RawMessage `db:"checker"`
+	Subtasks  json.RawMessage `db:"subtasks"`
 	CreatedAt time.Time       `db:"created_at"`
 	UpdatedAt time.Time       `db:"updated_at"`
 }
@@ -1,6 +1,39 @@
 package model
 
-import "time"
+import (
+	"slices"
+	"time"
+)
+
+const (
+	RoleStudent = "student"
+	RoleAuthor  = "author"
+	RoleAdmin   = "admin"
+)
+
+const (
+	// PermissionAuthorContent allows creating courses and editing the owned ones.
+	PermissionAuthorContent = "content:author"
+	// PermissionManageContent allows editing every course regardless of its owner.
+	PermissionManageContent = "content:manage"
+	// PermissionManageUsers allows changing roles and revoking sessions of other users.
+	PermissionManageUsers = "users:manage"
+)
+
+var rolePermissions = map[string][]string{
+	RoleStudent: {},
+	RoleAuthor:  {PermissionAuthorContent},
+	RoleAdmin:   {PermissionAuthorContent, PermissionManageContent, PermissionManageUsers},
+}
+
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+func HasPermission(role, permission string) bool {
+	return slices.Contains(rolePermissions[role], permission)
+}
 
 /*
 CREATE TABLE IF NOT EXISTS users (
@@ -10,6 +43,7 @@ CREATE TABLE IF NOT EXISTS users (
         AND length (login) <= 50
     ),
     hashed_password TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'student' CHECK (role IN ('student', 'author', 'admin')),
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW()
 );
@@ -19,6 +53,7 @@ type User struct {
 	ID             int       `db:"id"`
 	Login          string    `db:"login"`
 	HashedPassword string    `db:"hashed_password"`
+	Role           string    `db:"role"`
 	CreatedAt      time.Time `db:"created_at"`
 	UpdatedAt      time.Time `db:"updated_at"`
 }
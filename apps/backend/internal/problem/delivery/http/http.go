@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -17,6 +18,13 @@ import (
 type Service interface {
 	GetWithOptions(context.Context, int, ...service.Option) (*dto.Problem, error)
 	Submit(context.Context, *dto.ProblemSubmit) (int, error)
+	Run(context.Context, *dto.ProblemRun) (*dto.ProblemRunResult, error)
+	Create(context.Context, *dto.Problem) (*dto.Problem, error)
+	Update(context.Context, *dto.Problem) (*dto.Problem, error)
+	Delete(context.Context, int) error
+	Reorder(context.Context, int, []int) error
+	Validate(context.Context, int) (*dto.Validation, error)
+	GetValidation(context.Context, int) (*dto.Validation, error)
 }
 
 type Handler struct {
@@ -87,3 +95,166 @@ func (h *Handler) Submit(c fiber.Ctx) error {
 		AttemptID: attemptID,
 	})
 }
+
+func (h *Handler) Run(c fiber.Ctx) error {
+	runReq := &api.ProblemRunRequest{}
+	if err := c.Bind().JSON(runReq); err != nil {
+		return err
+	}
+
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		log.Error().Msg("Failed to get userID")
+		return fmt.Errorf("Failed to get userID")
+	}
+
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to parse problem id in query param")
+		return fmt.Errorf("failed to parse problem id in query param")
+	}
+
+	result, err := h.svc.Run(c.Context(), &dto.ProblemRun{
+		ProblemID: problemID,
+		UserID:    userID,
+		Language:  runReq.Language,
+		Code:      runReq.Code,
+		Inputs:    runReq.Inputs,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to run")
+		if errors.Is(err, service.ErrInvalidRun) {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+		return fmt.Errorf("failed to run")
+	}
+
+	return c.JSON(dto.RunResultToAPI(result))
+}
+
+func (h *Handler) Create(c fiber.Ctx) error {
+	lessonID, err := strconv.Atoi(c.Params("lessonID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	problemReq := &api.ProblemRequest{}
+	if err := c.Bind().JSON(problemReq); err != nil {
+		return err
+	}
+
+	problem, err := h.svc.Create(c.Context(), requestToDTO(0, lessonID, problemReq))
+	if err != nil {
+		return h.fail(c, err, "create problem")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToAPI(problem))
+}
+
+func (h *Handler) Update(c fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	problemReq := &api.ProblemRequest{}
+	if err := c.Bind().JSON(problemReq); err != nil {
+		return err
+	}
+
+	problem, err := h.svc.Update(c.Context(), requestToDTO(id, 0, problemReq))
+	if err != nil {
+		return h.fail(c, err, "update problem")
+	}
+
+	return c.JSON(dto.ToAPI(problem))
+}
+
+func (h *Handler) Delete(c fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if err := h.svc.Delete(c.Context(), id); err != nil {
+		return h.fail(c, err, "delete problem")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) Reorder(c fiber.Ctx) error {
+	lessonID, err := strconv.Atoi(c.Params("lessonID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	orderReq := &api.OrderRequest{}
+	if err := c.Bind().JSON(orderReq); err != nil {
+		return err
+	}
+
+	if err := h.svc.Reorder(c.Context(), lessonID, orderReq.IDs); err != nil {
+		return h.fail(c, err, "reorder problems")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Validate judges the reference solutions of the problem and returns the report.
+func (h *Handler) Validate(c fiber.Ctx) error {
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	validation, err := h.svc.Validate(c.Context(), problemID)
+	if err != nil {
+		return h.fail(c, err, "validate problem")
+	}
+
+	return c.JSON(dto.ValidationToAPI(validation))
+}
+
+func (h *Handler) GetValidation(c fiber.Ctx) error {
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	validation, err := h.svc.GetValidation(c.Context(), problemID)
+	if err != nil {
+		return h.fail(c, err, "get problem validation")
+	}
+
+	return c.JSON(dto.ValidationToAPI(validation))
+}
+
+// fail maps authoring errors to a status code, action is what failed, e.g. "create problem".
+func (h *Handler) fail(c fiber.Ctx, err error, action string) error {
+	log.Error().Err(err).Msg("Failed to " + action)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidProblem):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrValidationNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	default:
+		return fmt.Errorf("failed to %s", action)
+	}
+}
+
+func requestToDTO(id, lessonID int, req *api.ProblemRequest) *dto.Problem {
+	return &dto.Problem{
+		ID:          id,
+		LessonID:    lessonID,
+		Name:        req.Name,
+		Statement:   req.Statement,
+		Difficulty:  req.Difficulty,
+		TimeLimit:   req.TimeLimit,
+		MemoryLimit: req.MemoryLimit,
+		Position:    req.Position,
+		IOMode:      req.IOMode,
+		Signature:   req.Signature,
+	}
+}
@@ -2,14 +2,24 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"problum/internal/database"
 	"problum/internal/model"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog/log"
 )
 
+var (
+	ErrNotFound     = errors.New("problem not found")
+	ErrInvalidOrder = errors.New("order must list every problem of the lesson exactly once")
+
+	ErrValidationNotFound = errors.New("problem validation not found")
+)
+
 type Repository struct {
 	db *database.DB
 }
@@ -28,8 +38,11 @@ func (r *Repository) Get(ctx context.Context, id int) (*model.Problem, error) {
 		name,
 		statement,
 		difficulty,
+		io_mode,
+		signature,
 		time_limit,
 		memory_limit,
+		position,
 		created_at,
 		updated_at
 	FROM problems
@@ -43,11 +56,18 @@ func (r *Repository) Get(ctx context.Context, id int) (*model.Problem, error) {
 		&problem.Name,
 		&problem.Statement,
 		&problem.Difficulty,
+		&problem.IOMode,
+		&problem.Signature,
 		&problem.TimeLimit,
 		&problem.MemoryLimit,
+		&problem.Position,
 		&problem.CreatedAt,
 		&problem.UpdatedAt,
 	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
 		log.Error().Err(err).Msg("Failed to get problem")
 		return nil, fmt.Errorf("failed to get problem: %w", err)
 	}
@@ -63,12 +83,16 @@ func (r *Repository) ListByLessonID(ctx context.Context, id int) ([]*model.Probl
 		name,
 		statement,
 		difficulty,
+		io_mode,
+		signature,
 		time_limit,
 		memory_limit,
+		position,
 		created_at,
 		updated_at
 	FROM problems
 	WHERE lesson_id = $1
+	ORDER BY position, id
 	`
 
 	rows, err := r.db.Pool.Query(ctx, query, id)
@@ -87,8 +111,11 @@ func (r *Repository) ListByLessonID(ctx context.Context, id int) ([]*model.Probl
 			&problem.Name,
 			&problem.Statement,
 			&problem.Difficulty,
+			&problem.IOMode,
+			&problem.Signature,
 			&problem.TimeLimit,
 			&problem.MemoryLimit,
+			&problem.Position,
 			&problem.CreatedAt,
 			&problem.UpdatedAt,
 		); err != nil {
@@ -105,3 +132,352 @@ func (r *Repository) ListByLessonID(ctx context.Context, id int) ([]*model.Probl
 
 	return problems, nil
 }
+
+// Create appends the problem to the end of the lesson unless a position is given.
+func (r *Repository) Create(ctx context.Context, problem *model.Problem) (*model.Problem, error) {
+	query := `
+	INSERT INTO problems (lesson_id, name, statement, difficulty, time_limit, memory_limit, position, io_mode, signature)
+	SELECT
+		lessons.id,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6,
+		COALESCE(NULLIF($7, 0), (SELECT COALESCE(MAX(position), 0) + 1 FROM problems WHERE lesson_id = lessons.id)),
+		$8,
+		$9
+	FROM lessons
+	WHERE lessons.id = $1
+	RETURNING
+		id,
+		lesson_id,
+		name,
+		statement,
+		difficulty,
+		io_mode,
+		signature,
+		time_limit,
+		memory_limit,
+		position,
+		created_at,
+		updated_at
+	`
+
+	p := &model.Problem{}
+	if err := r.db.Pool.QueryRow(ctx, query,
+		problem.LessonID,
+		problem.Name,
+		problem.Statement,
+		problem.Difficulty,
+		problem.TimeLimit,
+		problem.MemoryLimit,
+		problem.Position,
+		problem.IOMode,
+		problem.Signature,
+	).Scan(
+		&p.ID,
+		&p.LessonID,
+		&p.Name,
+		&p.Statement,
+		&p.Difficulty,
+		&p.IOMode,
+		&p.Signature,
+		&p.TimeLimit,
+		&p.MemoryLimit,
+		&p.Position,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to create problem")
+		return nil, fmt.Errorf("failed to create problem: %w", err)
+	}
+
+	return p, nil
+}
+
+func (r *Repository) Update(ctx context.Context, problem *model.Problem) (*model.Problem, error) {
+	query := `
+	UPDATE problems
+	SET
+		name = $2,
+		statement = $3,
+		difficulty = $4,
+		time_limit = $5,
+		memory_limit = $6,
+		position = COALESCE(NULLIF($7, 0), position),
+		io_mode = $8,
+		signature = $9,
+		updated_at = NOW()
+	WHERE id = $1
+	RETURNING
+		id,
+		lesson_id,
+		name,
+		statement,
+		difficulty,
+		io_mode,
+		signature,
+		time_limit,
+		memory_limit,
+		position,
+		created_at,
+		updated_at
+	`
+
+	p := &model.Problem{}
+	if err := r.db.Pool.QueryRow(ctx, query,
+		problem.ID,
+		problem.Name,
+		problem.Statement,
+		problem.Difficulty,
+		problem.TimeLimit,
+		problem.MemoryLimit,
+		problem.Position,
+		problem.IOMode,
+		problem.Signature,
+	).Scan(
+		&p.ID,
+		&p.LessonID,
+		&p.Name,
+		&p.Statement,
+		&p.Difficulty,
+		&p.IOMode,
+		&p.Signature,
+		&p.TimeLimit,
+		&p.MemoryLimit,
+		&p.Position,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to update problem")
+		return nil, fmt.Errorf("failed to update problem: %w", err)
+	}
+
+	return p, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	query := `
+	DELETE FROM problems
+	WHERE id = $1
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete problem")
+		return fmt.Errorf("failed to delete problem: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Reorder numbers the problems of a lesson from 1 in the order of ids.
+func (r *Repository) Reorder(ctx context.Context, lessonID int, ids []int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin reorder problems transaction")
+		return fmt.Errorf("failed to begin reorder problems transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	selectQuery := `
+	SELECT id
+	FROM problems
+	WHERE lesson_id = $1
+	FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, selectQuery, lessonID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to lock problems of the lesson")
+		return fmt.Errorf("failed to lock problems of the lesson: %w", err)
+	}
+
+	current, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to collect problems of the lesson")
+		return fmt.Errorf("failed to collect problems of the lesson: %w", err)
+	}
+
+	if !sameIDs(current, ids) {
+		return ErrInvalidOrder
+	}
+
+	updateQuery := `
+	UPDATE problems
+	SET
+		position = ordered.position,
+		updated_at = NOW()
+	FROM unnest($2::int[]) WITH ORDINALITY AS ordered(id, position)
+	WHERE problems.id = ordered.id AND problems.lesson_id = $1
+	`
+
+	if _, err := tx.Exec(ctx, updateQuery, lessonID, ids); err != nil {
+		log.Error().Err(err).Msg("Failed to reorder problems")
+		return fmt.Errorf("failed to reorder problems: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to commit reorder problems transaction")
+		return fmt.Errorf("failed to commit reorder problems transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetValidation(ctx context.Context, problemID int) (*model.ProblemValidation, error) {
+	query := `
+	SELECT
+		problem_id,
+		passed,
+		time_limit,
+		memory_limit,
+		report,
+		validated_at
+	FROM problem_validations
+	WHERE problem_id = $1
+	`
+
+	validation := &model.ProblemValidation{}
+	if err := r.db.Pool.QueryRow(ctx, query, problemID).Scan(
+		&validation.ProblemID,
+		&validation.Passed,
+		&validation.TimeLimit,
+		&validation.MemoryLimit,
+		&validation.Report,
+		&validation.ValidatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrValidationNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to get problem validation")
+		return nil, fmt.Errorf("failed to get problem validation: %w", err)
+	}
+
+	return validation, nil
+}
+
+// SaveValidation replaces the last validation of a problem.
+func (r *Repository) SaveValidation(ctx context.Context, validation *model.ProblemValidation) (*model.ProblemValidation, error) {
+	query := `
+	INSERT INTO problem_validations (problem_id, passed, time_limit, memory_limit, report, validated_at)
+	SELECT problems.id, $2, $3, $4, $5, $6
+	FROM problems
+	WHERE problems.id = $1
+	ON CONFLICT (problem_id) DO UPDATE
+	SET
+		passed = EXCLUDED.passed,
+		time_limit = EXCLUDED.time_limit,
+		memory_limit = EXCLUDED.memory_limit,
+		report = EXCLUDED.report,
+		validated_at = EXCLUDED.validated_at
+	RETURNING
+		problem_id,
+		passed,
+		time_limit,
+		memory_limit,
+		report,
+		validated_at
+	`
+
+	v := &model.ProblemValidation{}
+	if err := r.db.Pool.QueryRow(ctx, query,
+		validation.ProblemID,
+		validation.Passed,
+		validation.TimeLimit,
+		validation.MemoryLimit,
+		validation.Report,
+		validation.ValidatedAt,
+	).Scan(
+		&v.ProblemID,
+		&v.Passed,
+		&v.TimeLimit,
+		&v.MemoryLimit,
+		&v.Report,
+		&v.ValidatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to save problem validation")
+		return nil, fmt.Errorf("failed to save problem validation: %w", err)
+	}
+
+	return v, nil
+}
+
+// ListUnvalidatedIDsByCourseID returns the problems of the course that failed
+// their last validation, were never validated, or changed their limits, tests,
+// templates or benchmark since.
+func (r *Repository) ListUnvalidatedIDsByCourseID(ctx context.Context, courseID int) ([]int, error) {
+	query := `
+	SELECT problems.id
+	FROM problems
+	JOIN lessons ON lessons.id = problems.lesson_id
+	LEFT JOIN problem_validations v ON v.problem_id = problems.id
+	WHERE lessons.course_id = $1
+		AND (
+			v.problem_id IS NULL
+			OR NOT v.passed
+			OR v.time_limit <> problems.time_limit
+			OR v.memory_limit <> problems.memory_limit
+			OR EXISTS (
+				SELECT 1
+				FROM tests
+				WHERE tests.problem_id = problems.id AND tests.updated_at > v.validated_at
+			)
+			OR EXISTS (
+				SELECT 1
+				FROM templates
+				WHERE templates.problem_id = problems.id AND templates.updated_at > v.validated_at
+			)
+			OR EXISTS (
+				SELECT 1
+				FROM benchmarks
+				WHERE benchmarks.problem_id = problems.id AND benchmarks.updated_at > v.validated_at
+			)
+		)
+	ORDER BY lessons.position, problems.position
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, courseID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list unvalidated problems")
+		return nil, fmt.Errorf("failed to list unvalidated problems: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to collect unvalidated problems")
+		return nil, fmt.Errorf("failed to collect unvalidated problems: %w", err)
+	}
+
+	return ids, nil
+}
+
+func sameIDs(current, ids []int) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	slices.Sort(current)
+
+	return slices.Equal(current, sorted)
+}
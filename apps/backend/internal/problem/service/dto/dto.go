@@ -1,11 +1,14 @@
 package dto
 
 import (
+	"encoding/json"
 	"time"
 
 	"problum/internal/api"
 	"problum/internal/model"
 	templateDTO "problum/internal/template/service/dto"
+
+	"github.com/bytedance/sonic"
 )
 
 type ProblemSubmit struct {
@@ -16,6 +19,73 @@ type ProblemSubmit struct {
 	Code      string
 }
 
+// ProblemRun is code to execute on custom inputs without creating an attempt.
+type ProblemRun struct {
+	ProblemID int
+	UserID    int
+	Language  string
+	Code      string
+	Inputs    []json.RawMessage
+}
+
+type ProblemRunResult struct {
+	Status       string
+	ErrorMessage *string
+	Outputs      []ProblemRunOutput
+}
+
+type ProblemRunOutput struct {
+	Status       string
+	Answer       string
+	Stdout       string
+	Stderr       string
+	Duration     time.Duration
+	MemoryUsage  int64
+	ExitCode     int
+	ErrorMessage *string
+}
+
+// ProblemValidate asks a worker to judge the reference solutions of a problem.
+type ProblemValidate struct {
+	ProblemID int
+}
+
+type ProblemValidateResult struct {
+	Status       string
+	Passed       bool
+	ErrorMessage *string
+	Languages    []ValidationLanguage
+}
+
+// Validation is the last run of the reference solutions of a problem, with
+// the limits it was judged with.
+type Validation struct {
+	ProblemID    int
+	Passed       bool
+	ErrorMessage *string
+	TimeLimit    time.Duration
+	MemoryLimit  int64
+	Languages    []ValidationLanguage
+	ValidatedAt  time.Time
+}
+
+// ValidationReport is what is stored of a validation beyond its verdict.
+type ValidationReport struct {
+	ErrorMessage *string              `json:"error_message,omitempty"`
+	Languages    []ValidationLanguage `json:"languages"`
+}
+
+type ValidationLanguage struct {
+	Language     string        `json:"language"`
+	Passed       bool          `json:"passed"`
+	Status       string        `json:"status"`
+	ErrorMessage *string       `json:"error_message,omitempty"`
+	Duration     time.Duration `json:"duration"`
+	MemoryUsage  int64         `json:"memory_usage"`
+	TimeBudget   time.Duration `json:"time_budget"`
+	MemoryBudget int64         `json:"memory_budget"`
+}
+
 type Problem struct {
 	ID          int
 	LessonID    int
@@ -24,6 +94,9 @@ type Problem struct {
 	Difficulty  string
 	TimeLimit   time.Duration
 	MemoryLimit int64
+	Position    int
+	IOMode      string
+	Signature   json.RawMessage
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Template    *templateDTO.Template
@@ -39,6 +112,9 @@ func ToDTO(problem *model.Problem, template *templateDTO.Template, languages []s
 		Difficulty:  problem.Difficulty,
 		TimeLimit:   problem.TimeLimit,
 		MemoryLimit: problem.MemoryLimit,
+		Position:    problem.Position,
+		IOMode:      problem.IOMode,
+		Signature:   problem.Signature,
 		CreatedAt:   problem.CreatedAt,
 		UpdatedAt:   problem.UpdatedAt,
 		Template:    template,
@@ -46,6 +122,23 @@ func ToDTO(problem *model.Problem, template *templateDTO.Template, languages []s
 	}
 }
 
+func ToModel(problem *Problem) *model.Problem {
+	return &model.Problem{
+		ID:          problem.ID,
+		LessonID:    problem.LessonID,
+		Name:        problem.Name,
+		Statement:   problem.Statement,
+		Difficulty:  problem.Difficulty,
+		TimeLimit:   problem.TimeLimit,
+		MemoryLimit: problem.MemoryLimit,
+		Position:    problem.Position,
+		IOMode:      problem.IOMode,
+		Signature:   problem.Signature,
+		CreatedAt:   problem.CreatedAt,
+		UpdatedAt:   problem.UpdatedAt,
+	}
+}
+
 func ToDTOList(problems []*model.Problem) []*Problem {
 	ans := make([]*Problem, 0, len(problems))
 
@@ -65,6 +158,9 @@ func ToAPI(problem *Problem) api.ProblemGetResponse {
 		Difficulty:  problem.Difficulty,
 		TimeLimit:   problem.TimeLimit,
 		MemoryLimit: problem.MemoryLimit,
+		Position:    problem.Position,
+		IOMode:      problem.IOMode,
+		Signature:   problem.Signature,
 		CreatedAt:   problem.CreatedAt,
 		UpdatedAt:   problem.UpdatedAt,
 		Template:    templateDTO.ToAPI(problem.Template),
@@ -81,3 +177,88 @@ func ToAPIList(problems []*Problem) []api.ProblemGetResponse {
 
 	return ans
 }
+
+func RunResultToAPI(result *ProblemRunResult) api.ProblemRunResponse {
+	outputs := make([]api.ProblemRunOutputResponse, 0, len(result.Outputs))
+	for _, output := range result.Outputs {
+		outputs = append(outputs, api.ProblemRunOutputResponse{
+			Status:       output.Status,
+			Answer:       output.Answer,
+			Stdout:       output.Stdout,
+			Stderr:       output.Stderr,
+			Duration:     output.Duration,
+			MemoryUsage:  output.MemoryUsage,
+			ExitCode:     output.ExitCode,
+			ErrorMessage: output.ErrorMessage,
+		})
+	}
+
+	return api.ProblemRunResponse{
+		Status:       result.Status,
+		ErrorMessage: result.ErrorMessage,
+		Outputs:      outputs,
+	}
+}
+
+func ValidationToDTO(validation *model.ProblemValidation) *Validation {
+	report := ValidationReport{}
+	sonic.Unmarshal(validation.Report, &report)
+	if report.Languages == nil {
+		report.Languages = make([]ValidationLanguage, 0)
+	}
+
+	return &Validation{
+		ProblemID:    validation.ProblemID,
+		Passed:       validation.Passed,
+		ErrorMessage: report.ErrorMessage,
+		TimeLimit:    validation.TimeLimit,
+		MemoryLimit:  validation.MemoryLimit,
+		Languages:    report.Languages,
+		ValidatedAt:  validation.ValidatedAt,
+	}
+}
+
+func ValidationToModel(validation *Validation) (*model.ProblemValidation, error) {
+	report, err := sonic.Marshal(ValidationReport{
+		ErrorMessage: validation.ErrorMessage,
+		Languages:    validation.Languages,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ProblemValidation{
+		ProblemID:   validation.ProblemID,
+		Passed:      validation.Passed,
+		TimeLimit:   validation.TimeLimit,
+		MemoryLimit: validation.MemoryLimit,
+		Report:      report,
+		ValidatedAt: validation.ValidatedAt,
+	}, nil
+}
+
+func ValidationToAPI(validation *Validation) api.ProblemValidationResponse {
+	languages := make([]api.ProblemValidationLanguageResponse, 0, len(validation.Languages))
+	for _, language := range validation.Languages {
+		languages = append(languages, api.ProblemValidationLanguageResponse{
+			Language:     language.Language,
+			Passed:       language.Passed,
+			Status:       language.Status,
+			ErrorMessage: language.ErrorMessage,
+			Duration:     language.Duration,
+			MemoryUsage:  language.MemoryUsage,
+			TimeBudget:   language.TimeBudget,
+			MemoryBudget: language.MemoryBudget,
+		})
+	}
+
+	return api.ProblemValidationResponse{
+		ProblemID:    validation.ProblemID,
+		Passed:       validation.Passed,
+		ErrorMessage: validation.ErrorMessage,
+		TimeLimit:    validation.TimeLimit,
+		MemoryLimit:  validation.MemoryLimit,
+		Languages:    languages,
+		ValidatedAt:  validation.ValidatedAt,
+	}
+}
@@ -2,11 +2,18 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	attemptDTO "problum/internal/attempt/service/dto"
 	"problum/internal/model"
+	"problum/internal/problem/repository"
 	"problum/internal/problem/service/dto"
+	"problum/internal/signature"
 	templateDTO "problum/internal/template/service/dto"
 
 	"github.com/bytedance/sonic"
@@ -14,9 +21,39 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	// maxRunInputs caps how many custom inputs a single run may execute.
+	maxRunInputs    = 5
+	runTimeout      = 30 * time.Second
+	validateTimeout = 5 * time.Minute
+
+	// maxTimeLimit and maxMemoryLimit keep authored limits within what a worker can afford per test.
+	maxTimeLimit   = 10 * time.Second
+	maxMemoryLimit = 1024 * 1024 * 1024
+)
+
+var (
+	ErrInvalidRun     = errors.New("invalid run")
+	ErrInvalidProblem = errors.New("invalid problem")
+	ErrNotFound       = repository.ErrNotFound
+
+	ErrValidationNotFound = repository.ErrValidationNotFound
+)
+
+var difficulties = []string{"easy", "medium", "hard"}
+
+var ioModes = []string{model.IOModeFunction, model.IOModeStdio, model.IOModeInteractive, model.IOModeGoTest, model.IOModeDesign}
+
 type Repository interface {
 	Get(context.Context, int) (*model.Problem, error)
 	ListByLessonID(context.Context, int) ([]*model.Problem, error)
+	Create(context.Context, *model.Problem) (*model.Problem, error)
+	Update(context.Context, *model.Problem) (*model.Problem, error)
+	Delete(context.Context, int) error
+	Reorder(context.Context, int, []int) error
+	GetValidation(context.Context, int) (*model.ProblemValidation, error)
+	SaveValidation(context.Context, *model.ProblemValidation) (*model.ProblemValidation, error)
+	ListUnvalidatedIDsByCourseID(context.Context, int) ([]int, error)
 }
 
 type AttemptService interface {
@@ -147,6 +184,11 @@ func (s *Service) ListByLessonID(ctx context.Context, lessonID int) ([]*dto.Prob
 }
 
 func (s *Service) Submit(ctx context.Context, submit *dto.ProblemSubmit) (int, error) {
+	if _, err := s.templateSvc.GetByProblemIDAndLanguage(ctx, submit.ProblemID, submit.Language); err != nil {
+		log.Error().Err(err).Str("language", submit.Language).Msg("Language is not available for problem")
+		return 0, fmt.Errorf("language is not available for problem: %w", err)
+	}
+
 	id, err := s.attemptSvc.Submit(ctx, &attemptDTO.Attempt{
 		ProblemID: submit.ProblemID,
 		UserID:    submit.UserID,
@@ -173,3 +215,243 @@ func (s *Service) Submit(ctx context.Context, submit *dto.ProblemSubmit) (int, e
 
 	return id, nil
 }
+
+// Run executes the code on custom inputs through the worker and waits for the
+// output. Nothing is stored: the worker replies over a plain NATS request.
+func (s *Service) Run(ctx context.Context, run *dto.ProblemRun) (*dto.ProblemRunResult, error) {
+	if len(run.Inputs) == 0 || len(run.Inputs) > maxRunInputs {
+		return nil, fmt.Errorf("%w: expected from 1 to %d inputs", ErrInvalidRun, maxRunInputs)
+	}
+
+	for i, input := range run.Inputs {
+		if !json.Valid(input) {
+			return nil, fmt.Errorf("%w: input %d is not valid JSON", ErrInvalidRun, i)
+		}
+	}
+
+	problem, err := s.repo.Get(ctx, run.ProblemID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get problem")
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+
+	// there is nothing to talk to without the interactor, which only runs on tests
+	if problem.IOMode == model.IOModeInteractive {
+		return nil, fmt.Errorf("%w: interactive problems can not be run on custom inputs", ErrInvalidRun)
+	}
+
+	// a package has no input of its own, only the hidden tests exercise it
+	if problem.IOMode == model.IOModeGoTest {
+		return nil, fmt.Errorf("%w: gotest problems can not be run on custom inputs", ErrInvalidRun)
+	}
+
+	if _, err := s.templateSvc.GetByProblemIDAndLanguage(ctx, run.ProblemID, run.Language); err != nil {
+		log.Error().Err(err).Str("language", run.Language).Msg("Language is not available for problem")
+		return nil, fmt.Errorf("%w: language is not available for problem", ErrInvalidRun)
+	}
+
+	payload, err := sonic.Marshal(run)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal payload")
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	reply, err := s.js.Conn().RequestWithContext(ctx, "RUNS.new", payload)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to request run")
+		return nil, fmt.Errorf("failed to request run: %w", err)
+	}
+
+	result := &dto.ProblemRunResult{}
+	if err := sonic.Unmarshal(reply.Data, result); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal run result")
+		return nil, fmt.Errorf("failed to unmarshal run result: %w", err)
+	}
+
+	return result, nil
+}
+
+// Validate has a worker judge every reference solution of the problem on its
+// tests and stores the report. A problem whose limits, tests or templates
+// changed since its last passed validation has to be validated again.
+func (s *Service) Validate(ctx context.Context, problemID int) (*dto.Validation, error) {
+	problem, err := s.repo.Get(ctx, problemID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get problem")
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+
+	// changes made while the solutions are being judged make the report stale
+	startedAt := time.Now()
+
+	payload, err := sonic.Marshal(&dto.ProblemValidate{ProblemID: problemID})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal payload")
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, validateTimeout)
+	defer cancel()
+
+	reply, err := s.js.Conn().RequestWithContext(ctx, "PROBLEMS.validate", payload)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to request validation")
+		return nil, fmt.Errorf("failed to request validation: %w", err)
+	}
+
+	result := &dto.ProblemValidateResult{}
+	if err := sonic.Unmarshal(reply.Data, result); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal validation result")
+		return nil, fmt.Errorf("failed to unmarshal validation result: %w", err)
+	}
+
+	if result.Status != "OK" {
+		return nil, fmt.Errorf("failed to validate problem: worker answered %s", result.Status)
+	}
+
+	m, err := dto.ValidationToModel(&dto.Validation{
+		ProblemID:    problemID,
+		Passed:       result.Passed,
+		ErrorMessage: result.ErrorMessage,
+		TimeLimit:    problem.TimeLimit,
+		MemoryLimit:  problem.MemoryLimit,
+		Languages:    result.Languages,
+		ValidatedAt:  startedAt,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to convert validation")
+		return nil, fmt.Errorf("failed to convert validation: %w", err)
+	}
+
+	saved, err := s.repo.SaveValidation(ctx, m)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to save validation")
+		return nil, fmt.Errorf("failed to save validation: %w", err)
+	}
+
+	return dto.ValidationToDTO(saved), nil
+}
+
+func (s *Service) GetValidation(ctx context.Context, problemID int) (*dto.Validation, error) {
+	validation, err := s.repo.GetValidation(ctx, problemID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get validation")
+		return nil, fmt.Errorf("failed to get validation: %w", err)
+	}
+
+	return dto.ValidationToDTO(validation), nil
+}
+
+// ListUnvalidatedIDsByCourseID returns the problems keeping the course from
+// being published: those without a passed validation that is still current.
+func (s *Service) ListUnvalidatedIDsByCourseID(ctx context.Context, courseID int) ([]int, error) {
+	ids, err := s.repo.ListUnvalidatedIDsByCourseID(ctx, courseID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list unvalidated problems")
+		return nil, fmt.Errorf("failed to list unvalidated problems: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (s *Service) Create(ctx context.Context, problem *dto.Problem) (*dto.Problem, error) {
+	if err := validate(problem); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(ctx, dto.ToModel(problem))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create problem")
+		return nil, fmt.Errorf("failed to create problem: %w", err)
+	}
+
+	return dto.ToDTO(created, nil, nil), nil
+}
+
+func (s *Service) Update(ctx context.Context, problem *dto.Problem) (*dto.Problem, error) {
+	if err := validate(problem); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.Update(ctx, dto.ToModel(problem))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update problem")
+		return nil, fmt.Errorf("failed to update problem: %w", err)
+	}
+
+	return dto.ToDTO(updated, nil, nil), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		log.Error().Err(err).Msg("Failed to delete problem")
+		return fmt.Errorf("failed to delete problem: %w", err)
+	}
+
+	return nil
+}
+
+// Reorder takes the ids of all problems of the lesson in their new order.
+func (s *Service) Reorder(ctx context.Context, lessonID int, ids []int) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: order is empty", ErrInvalidProblem)
+	}
+
+	if err := s.repo.Reorder(ctx, lessonID, ids); err != nil {
+		log.Error().Err(err).Msg("Failed to reorder problems")
+		if errors.Is(err, repository.ErrInvalidOrder) {
+			return fmt.Errorf("%w: %w", ErrInvalidProblem, err)
+		}
+		return fmt.Errorf("failed to reorder problems: %w", err)
+	}
+
+	return nil
+}
+
+func validate(problem *dto.Problem) error {
+	if strings.TrimSpace(problem.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidProblem)
+	}
+
+	if !slices.Contains(difficulties, problem.Difficulty) {
+		return fmt.Errorf("%w: difficulty must be one of %s", ErrInvalidProblem, strings.Join(difficulties, ", "))
+	}
+
+	if problem.IOMode == "" {
+		problem.IOMode = model.IOModeFunction
+	}
+
+	if !slices.Contains(ioModes, problem.IOMode) {
+		return fmt.Errorf("%w: io mode must be one of %s", ErrInvalidProblem, strings.Join(ioModes, ", "))
+	}
+
+	if problem.TimeLimit <= 0 || problem.TimeLimit > maxTimeLimit {
+		return fmt.Errorf("%w: time limit must be positive and at most %s", ErrInvalidProblem, maxTimeLimit)
+	}
+
+	if problem.MemoryLimit <= 0 || problem.MemoryLimit > maxMemoryLimit {
+		return fmt.Errorf("%w: memory limit must be positive and at most %d bytes", ErrInvalidProblem, maxMemoryLimit)
+	}
+
+	if problem.Position < 0 {
+		return fmt.Errorf("%w: position must not be negative", ErrInvalidProblem)
+	}
+
+	if len(problem.Signature) == 0 || string(problem.Signature) == "null" {
+		problem.Signature = nil
+		return nil
+	}
+
+	if problem.IOMode != model.IOModeFunction {
+		return fmt.Errorf("%w: only function problems have a signature", ErrInvalidProblem)
+	}
+
+	if _, err := signature.Parse(problem.Signature); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProblem, err)
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"strings"
+
 	"problum/internal/config"
 
 	"github.com/bytedance/sonic"
@@ -24,6 +26,10 @@ func New(cfg *config.Config) *fiber.App {
 	app.Use(requestid.New())
 	app.Use(cors.New())
 	app.Use(compress.New(compress.Config{
+		// compression reads the whole body, which never ends for event streams
+		Next: func(c fiber.Ctx) bool {
+			return strings.HasSuffix(c.Path(), "/events")
+		},
 		Level: compress.LevelBestSpeed,
 	}))
 	app.Use(logger.New())
@@ -211,3 +211,95 @@ func (r *Repository) LogoutAll(ctx context.Context, id int) error {
 
 	return nil
 }
+
+func (r *Repository) Get(ctx context.Context, id int) (*model.UserSession, error) {
+	query := `
+	SELECT
+		id,
+		user_id,
+		refresh_hash,
+		previous_refresh_hash,
+		expires_at,
+		revoked,
+		last_activity_at,
+		created_at,
+		updated_at
+	FROM user_sessions
+	WHERE id = $1
+	`
+
+	s := &model.UserSession{}
+	if err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+		&s.ID,
+		&s.UserID,
+		&s.RefreshHash,
+		&s.PreviousRefreshHash,
+		&s.ExpiresAt,
+		&s.Revoked,
+		&s.LastActivityAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to get user session")
+		return nil, fmt.Errorf("failed to get user session: %w", err)
+	}
+
+	return s, nil
+}
+
+// ListActiveByUserID returns the sessions of a user that are neither revoked nor expired.
+func (r *Repository) ListActiveByUserID(ctx context.Context, userID int) ([]*model.UserSession, error) {
+	query := `
+	SELECT
+		id,
+		user_id,
+		refresh_hash,
+		previous_refresh_hash,
+		expires_at,
+		revoked,
+		last_activity_at,
+		created_at,
+		updated_at
+	FROM user_sessions
+	WHERE user_id = $1 AND revoked = false AND expires_at > NOW()
+	ORDER BY last_activity_at DESC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create user sessions list query")
+		return nil, fmt.Errorf("failed to create user sessions list query: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]*model.UserSession, 0)
+	for rows.Next() {
+		s := &model.UserSession{}
+		if err := rows.Scan(
+			&s.ID,
+			&s.UserID,
+			&s.RefreshHash,
+			&s.PreviousRefreshHash,
+			&s.ExpiresAt,
+			&s.Revoked,
+			&s.LastActivityAt,
+			&s.CreatedAt,
+			&s.UpdatedAt,
+		); err != nil {
+			log.Error().Err(err).Msg("Failed to scan user session")
+			return nil, fmt.Errorf("failed to scan user session: %w", err)
+		}
+
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to iterate user sessions")
+		return nil, fmt.Errorf("failed to iterate user sessions: %w", err)
+	}
+
+	return sessions, nil
+}
@@ -12,6 +12,8 @@ type Repository interface {
 	Update(context.Context, *model.UserSession) (*model.UserSession, error)
 	GetByPreviousRefreshHash(context.Context, string) (*model.UserSession, error)
 	LogoutAll(context.Context, int) error
+	Get(context.Context, int) (*model.UserSession, error)
+	ListActiveByUserID(context.Context, int) ([]*model.UserSession, error)
 }
 
 type Service struct {
@@ -43,3 +45,11 @@ func (s *Service) Update(ctx context.Context, session *model.UserSession) (*mode
 func (s *Service) LogoutAll(ctx context.Context, id int) error {
 	return s.repo.LogoutAll(ctx, id)
 }
+
+func (s *Service) Get(ctx context.Context, id int) (*model.UserSession, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *Service) ListActiveByUserID(ctx context.Context, userID int) ([]*model.UserSession, error) {
+	return s.repo.ListActiveByUserID(ctx, userID)
+}
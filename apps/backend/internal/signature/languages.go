@@ -0,0 +1,408 @@
+package signature
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Template is the starter code and the harness metadata a language gets for
+// a signature.
+type Template struct {
+	Code     string
+	Metadata Metadata
+}
+
+// Metadata is what the harness of a language is rendered with, see the
+// metadata of templates.
+type Metadata struct {
+	FunctionName string      `json:"function_name"`
+	Parameters   []Parameter `json:"parameters"`
+	ReturnType   string      `json:"return_type,omitempty"`
+}
+
+// language knows the type names of a harness and how starter code looks.
+type language struct {
+	// typeName returns the type as the harness spells it, false when the
+	// harness does not support it.
+	typeName     func(*Type) (string, bool)
+	functionName func(string) string
+	starter      func(fn string, params []starterParam, ret *Type) string
+	// reserved are the words the language does not take as names.
+	reserved map[string]bool
+}
+
+type starterParam struct {
+	Name string
+	Type *Type
+}
+
+var languages = map[string]*language{
+	"go": {
+		typeName:     goType,
+		functionName: func(name string) string { return name },
+		starter:      goStarter,
+		reserved: words(`break case chan const continue default defer else fallthrough for func go goto if
+			import interface map package range return select struct switch type var`),
+	},
+	"python": {
+		typeName:     pythonType,
+		functionName: snakeCase,
+		starter:      pythonStarter,
+		reserved: words(`False None True and as assert async await break class continue def del elif else
+			except finally for from global if import in is lambda nonlocal not or pass raise return try
+			while with yield`),
+	},
+	"cpp": {
+		typeName:     cppType,
+		functionName: func(name string) string { return name },
+		starter:      cppStarter,
+		reserved: words(`alignas alignof and and_eq asm auto bitand bitor bool break case catch char
+			char8_t char16_t char32_t class compl concept const consteval constexpr constinit const_cast
+			continue co_await co_return co_yield decltype default delete do double dynamic_cast else enum
+			explicit export extern false float for friend goto if inline int long mutable namespace new
+			noexcept not not_eq nullptr operator or or_eq private protected public register
+			reinterpret_cast requires return short signed sizeof static static_assert static_cast struct
+			switch template this thread_local throw true try typedef typeid typename union unsigned using
+			virtual void volatile wchar_t while xor xor_eq`),
+	},
+	"rust": {
+		typeName:     rustType,
+		functionName: snakeCase,
+		starter:      rustStarter,
+		reserved: words(`as async await break const continue crate dyn else enum extern false fn for gen if
+			impl in let loop match mod move mut pub ref return self Self static struct super trait true
+			type unsafe use where while abstract become box do final macro override priv try typeof
+			unsized virtual yield`),
+	},
+}
+
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(s) {
+		set[word] = true
+	}
+
+	return set
+}
+
+// reservedIn returns the first language that reserves the name, spelled as
+// the language names functions when function is set.
+func reservedIn(name string, function bool) (string, bool) {
+	for _, languageName := range Languages() {
+		lang := languages[languageName]
+
+		spelled := name
+		if function {
+			spelled = lang.functionName(name)
+		}
+
+		if lang.reserved[spelled] {
+			return languageName, true
+		}
+	}
+
+	return "", false
+}
+
+// Languages returns the languages templates can be generated for.
+func Languages() []string {
+	names := make([]string, 0, len(languages))
+	for name := range languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Generate returns the template of the language for a parsed signature.
+// ErrUnsupported means the language is unknown or lacks one of the types.
+func Generate(sig *Signature, languageName string) (*Template, error) {
+	lang, ok := languages[languageName]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown language %s", ErrUnsupported, languageName)
+	}
+
+	metadata := Metadata{
+		FunctionName: lang.functionName(sig.FunctionName),
+		Parameters:   make([]Parameter, 0, len(sig.Parameters)),
+	}
+
+	params := make([]starterParam, 0, len(sig.Parameters))
+	for _, param := range sig.Parameters {
+		t, err := ParseType(param.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		name, ok := lang.typeName(t)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no %s", ErrUnsupported, languageName, param.Type)
+		}
+
+		metadata.Parameters = append(metadata.Parameters, Parameter{Name: param.Name, Type: name})
+		params = append(params, starterParam{Name: param.Name, Type: t})
+	}
+
+	ret, err := ParseType(sig.ReturnType)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata.ReturnType = KindVoid
+	if ret.Kind != KindVoid {
+		name, ok := lang.typeName(ret)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no %s", ErrUnsupported, languageName, sig.ReturnType)
+		}
+		metadata.ReturnType = name
+	}
+
+	return &Template{
+		Code:     lang.starter(metadata.FunctionName, params, ret),
+		Metadata: metadata,
+	}, nil
+}
+
+func goType(t *Type) (string, bool) {
+	switch t.Kind {
+	case KindInt:
+		return "int", true
+	case KindLong:
+		return "int64", true
+	case KindDouble:
+		return "float64", true
+	case KindBool:
+		return "bool", true
+	case KindString:
+		return "string", true
+	case KindList, KindTree, KindGraph:
+		return "*" + t.Kind, true
+	case KindArray:
+		elem, ok := goType(t.Elem)
+		return "[]" + elem, ok
+	case KindMap:
+		elem, ok := goType(t.Elem)
+		return "map[string]" + elem, ok
+	}
+
+	return "", false
+}
+
+func goStarter(fn string, params []starterParam, ret *Type) string {
+	args := make([]string, 0, len(params))
+	for _, param := range params {
+		name, _ := goType(param.Type)
+		args = append(args, param.Name+" "+name)
+	}
+
+	if ret.Kind == KindVoid {
+		return fmt.Sprintf("func %s(%s) {\n\t// Ваша реализация\n}\n", fn, strings.Join(args, ", "))
+	}
+
+	name, _ := goType(ret)
+	zero := "nil"
+	switch ret.Kind {
+	case KindInt, KindLong, KindDouble:
+		zero = "0"
+	case KindBool:
+		zero = "false"
+	case KindString:
+		zero = `""`
+	}
+
+	return fmt.Sprintf("func %s(%s) %s {\n\t// Ваша реализация\n\treturn %s\n}\n", fn, strings.Join(args, ", "), name, zero)
+}
+
+// pythonType spells types the way the Python harness decodes them. It only
+// builds nodes within List[...] and Optional[...], not within dicts.
+func pythonType(t *Type) (string, bool) {
+	switch t.Kind {
+	case KindInt, KindLong:
+		return "int", true
+	case KindDouble:
+		return "float", true
+	case KindBool:
+		return "bool", true
+	case KindString:
+		return "str", true
+	case KindList, KindTree, KindGraph:
+		return "Optional[" + t.Kind + "]", true
+	case KindArray:
+		elem, ok := pythonType(t.Elem)
+		return "List[" + elem + "]", ok
+	case KindMap:
+		if t.Elem.HasNodes() {
+			return "", false
+		}
+		elem, ok := pythonType(t.Elem)
+		return "Dict[str, " + elem + "]", ok
+	}
+
+	return "", false
+}
+
+func pythonStarter(fn string, params []starterParam, ret *Type) string {
+	args := make([]string, 0, len(params))
+	for _, param := range params {
+		name, _ := pythonType(param.Type)
+		args = append(args, param.Name+": "+name)
+	}
+
+	if ret.Kind == KindVoid {
+		return fmt.Sprintf("def %s(%s) -> None:\n    # Ваша реализация\n    pass\n", fn, strings.Join(args, ", "))
+	}
+
+	name, _ := pythonType(ret)
+	zero := "None"
+	switch ret.Kind {
+	case KindInt, KindLong:
+		zero = "0"
+	case KindDouble:
+		zero = "0.0"
+	case KindBool:
+		zero = "False"
+	case KindString:
+		zero = `""`
+	case KindArray:
+		zero = "[]"
+	case KindMap:
+		zero = "{}"
+	}
+
+	return fmt.Sprintf("def %s(%s) -> %s:\n    # Ваша реализация\n    return %s\n", fn, strings.Join(args, ", "), name, zero)
+}
+
+// cppType has no nodes, the C++ harness does not build them.
+func cppType(t *Type) (string, bool) {
+	switch t.Kind {
+	case KindInt, KindBool, KindDouble:
+		return t.Kind, true
+	case KindLong:
+		return "long long", true
+	case KindString:
+		return "std::string", true
+	case KindArray:
+		elem, ok := cppType(t.Elem)
+		return "std::vector<" + elem + ">", ok
+	case KindMap:
+		elem, ok := cppType(t.Elem)
+		return "std::map<std::string, " + elem + ">", ok
+	}
+
+	return "", false
+}
+
+// cppStarter passes containers by const reference, and the first parameter
+// of a void function by reference as that is what it is judged by.
+func cppStarter(fn string, params []starterParam, ret *Type) string {
+	// the starter code is compiled with using namespace std
+	spell := func(t *Type) string {
+		name, _ := cppType(t)
+		return strings.ReplaceAll(name, "std::", "")
+	}
+
+	args := make([]string, 0, len(params))
+	for i, param := range params {
+		name := spell(param.Type)
+		switch {
+		case i == 0 && ret.Kind == KindVoid:
+			name += "&"
+		case param.Type.Kind == KindString || param.Type.Kind == KindArray || param.Type.Kind == KindMap:
+			name = "const " + name + "&"
+		}
+		args = append(args, name+" "+param.Name)
+	}
+
+	if ret.Kind == KindVoid {
+		return fmt.Sprintf("void %s(%s) {\n    // Ваша реализация\n}\n", fn, strings.Join(args, ", "))
+	}
+
+	zero := "{}"
+	switch ret.Kind {
+	case KindInt, KindLong:
+		zero = "0"
+	case KindDouble:
+		zero = "0.0"
+	case KindBool:
+		zero = "false"
+	}
+
+	return fmt.Sprintf("%s %s(%s) {\n    // Ваша реализация\n    return %s;\n}\n", spell(ret), fn, strings.Join(args, ", "), zero)
+}
+
+// rustType has no nodes, the Rust harness does not build them.
+func rustType(t *Type) (string, bool) {
+	switch t.Kind {
+	case KindInt:
+		return "i32", true
+	case KindLong:
+		return "i64", true
+	case KindDouble:
+		return "f64", true
+	case KindBool:
+		return "bool", true
+	case KindString:
+		return "String", true
+	case KindArray:
+		elem, ok := rustType(t.Elem)
+		return "Vec<" + elem + ">", ok
+	case KindMap:
+		elem, ok := rustType(t.Elem)
+		return "HashMap<String, " + elem + ">", ok
+	}
+
+	return "", false
+}
+
+// rustStarter takes the first parameter of a void function as &mut, the
+// harness lends it to the function and judges what is left in it.
+func rustStarter(fn string, params []starterParam, ret *Type) string {
+	var b strings.Builder
+
+	usesMap := false
+	for _, param := range params {
+		name, _ := rustType(param.Type)
+		usesMap = usesMap || strings.Contains(name, "HashMap")
+	}
+	if ret.Kind != KindVoid {
+		name, _ := rustType(ret)
+		usesMap = usesMap || strings.Contains(name, "HashMap")
+	}
+	if usesMap {
+		b.WriteString("use std::collections::HashMap;\n\n")
+	}
+
+	args := make([]string, 0, len(params))
+	for i, param := range params {
+		name, _ := rustType(param.Type)
+		if i == 0 && ret.Kind == KindVoid {
+			name = "&mut " + name
+		}
+		args = append(args, param.Name+": "+name)
+	}
+
+	if ret.Kind == KindVoid {
+		fmt.Fprintf(&b, "fn %s(%s) {\n    // Ваша реализация\n}\n", fn, strings.Join(args, ", "))
+		return b.String()
+	}
+
+	name, _ := rustType(ret)
+	zero := name + "::new()"
+	switch ret.Kind {
+	case KindInt, KindLong:
+		zero = "0"
+	case KindDouble:
+		zero = "0.0"
+	case KindBool:
+		zero = "false"
+	case KindArray:
+		zero = "Vec::new()"
+	case KindMap:
+		zero = "HashMap::new()"
+	}
+
+	fmt.Fprintf(&b, "fn %s(%s) -> %s {\n    // Ваша реализация\n    %s\n}\n", fn, strings.Join(args, ", "), name, zero)
+	return b.String()
+}
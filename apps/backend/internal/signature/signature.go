@@ -0,0 +1,195 @@
+package signature
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ErrUnsupported means a language can not express the signature, its harness
+// does not know one of the types.
+var ErrUnsupported = errors.New("signature is not supported by the language")
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Signature is the function of a problem written once for every language.
+// Types are one of int, long, double, bool, string, ListNode, TreeNode and
+// GraphNode, arrays of a type like int[] and objects like map<string,int>.
+// A void function is judged by the value of its first parameter.
+type Signature struct {
+	FunctionName string      `json:"function_name"`
+	Parameters   []Parameter `json:"parameters"`
+	ReturnType   string      `json:"return_type"`
+}
+
+type Parameter struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Kinds of types.
+const (
+	KindInt    = "int"
+	KindLong   = "long"
+	KindDouble = "double"
+	KindBool   = "bool"
+	KindString = "string"
+	KindList   = "ListNode"
+	KindTree   = "TreeNode"
+	KindGraph  = "GraphNode"
+	KindArray  = "array"
+	KindMap    = "map"
+	KindVoid   = "void"
+)
+
+// Type is a parsed type of a signature. Elem is the element of an array and
+// the value of a map, whose keys are always strings as they are in JSON.
+type Type struct {
+	Kind string
+	Elem *Type
+}
+
+// Parse reads and validates a signature.
+func Parse(raw json.RawMessage) (*Signature, error) {
+	sig := &Signature{}
+	if err := json.Unmarshal(raw, sig); err != nil {
+		return nil, fmt.Errorf("signature is not a valid object: %w", err)
+	}
+
+	if err := sig.validate(); err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+func (s *Signature) validate() error {
+	if !identifier.MatchString(s.FunctionName) {
+		return fmt.Errorf("function_name must be an identifier")
+	}
+
+	// names are not escaped, each language has to take them as they are
+	if lang, ok := reservedIn(s.FunctionName, true); ok {
+		return fmt.Errorf("function_name %s is a reserved word in %s", s.FunctionName, lang)
+	}
+
+	names := make(map[string]bool, len(s.Parameters))
+	for i, param := range s.Parameters {
+		if !identifier.MatchString(param.Name) || !unicode.IsLetter(rune(param.Name[0])) {
+			return fmt.Errorf("name of parameter %d must be an identifier starting with a letter", i)
+		}
+
+		if lang, ok := reservedIn(param.Name, false); ok {
+			return fmt.Errorf("parameter %s is a reserved word in %s", param.Name, lang)
+		}
+
+		// the Go harness names the arguments after the capitalized parameters
+		name := strings.ToUpper(param.Name[:1]) + param.Name[1:]
+		if names[name] {
+			return fmt.Errorf("duplicate parameter %s", param.Name)
+		}
+		names[name] = true
+
+		t, err := ParseType(param.Type)
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", param.Name, err)
+		}
+		if t.Kind == KindVoid {
+			return fmt.Errorf("parameter %s can not be void", param.Name)
+		}
+	}
+
+	t, err := ParseType(s.ReturnType)
+	if err != nil {
+		return fmt.Errorf("return type: %w", err)
+	}
+
+	if t.Kind == KindVoid && len(s.Parameters) == 0 {
+		return fmt.Errorf("a void function needs a parameter to return")
+	}
+
+	return nil
+}
+
+// ParseType reads a type like int[][] or map<string,TreeNode>.
+func ParseType(s string) (*Type, error) {
+	s = strings.TrimSpace(s)
+
+	switch s {
+	case KindInt, KindLong, KindDouble, KindBool, KindString, KindList, KindTree, KindGraph, KindVoid:
+		return &Type{Kind: s}, nil
+	}
+
+	if elem, ok := strings.CutSuffix(s, "[]"); ok {
+		t, err := parseValueType(elem)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Type{Kind: KindArray, Elem: t}, nil
+	}
+
+	if inner, ok := strings.CutPrefix(s, "map<"); ok && strings.HasSuffix(inner, ">") {
+		key, value, ok := strings.Cut(strings.TrimSuffix(inner, ">"), ",")
+		if !ok {
+			return nil, fmt.Errorf("map %s needs a key and a value type", s)
+		}
+
+		if strings.TrimSpace(key) != KindString {
+			return nil, fmt.Errorf("keys of map %s must be strings", s)
+		}
+
+		t, err := parseValueType(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Type{Kind: KindMap, Elem: t}, nil
+	}
+
+	return nil, fmt.Errorf("unknown type %q", s)
+}
+
+func parseValueType(s string) (*Type, error) {
+	t, err := ParseType(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Kind == KindVoid {
+		return nil, fmt.Errorf("void is not a value")
+	}
+
+	return t, nil
+}
+
+// HasNodes tells whether values of the type hold nodes somewhere.
+func (t *Type) HasNodes() bool {
+	switch t.Kind {
+	case KindList, KindTree, KindGraph:
+		return true
+	case KindArray, KindMap:
+		return t.Elem.HasNodes()
+	}
+
+	return false
+}
+
+// snakeCase turns twoSum into two_sum, for the languages naming functions so.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 && name[i-1] != '_' {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
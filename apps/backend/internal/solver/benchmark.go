@@ -0,0 +1,91 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"problum/internal/complexity"
+	"problum/internal/solver/dto"
+	"problum/internal/utils"
+
+	testService "problum/internal/test/service"
+	testDTO "problum/internal/test/service/dto"
+
+	"github.com/rs/zerolog/log"
+)
+
+// benchmarkRuns is how many times every benchmark input is run. The fastest
+// run is taken, the others only add how busy the machine was.
+const benchmarkRuns = 3
+
+// getBenchmark returns the benchmark of the problem, nil when it has none.
+func (s *Solver) getBenchmark(ctx context.Context, problemID int) (*testDTO.Benchmark, error) {
+	benchmark, err := s.testSvc.GetBenchmark(ctx, problemID)
+	if err != nil {
+		if errors.Is(err, testService.ErrBenchmarkNotFound) {
+			return nil, nil
+		}
+
+		log.Error().Err(err).Msg("Failed to get benchmark")
+		return nil, fmt.Errorf("failed to get benchmark: %w", err)
+	}
+
+	return benchmark, nil
+}
+
+// runBenchmark times the accepted submission on the inputs of the benchmark
+// and estimates its complexity. A submission above the complexity the problem
+// allows, or failing on an input it is timed on, loses the acceptance and the
+// score. Without a required complexity a failing run only leaves the
+// complexity unknown.
+func runBenchmark(
+	cfg *runConfig,
+	sub *submission,
+	benchmark *testDTO.Benchmark,
+	result *dto.Result,
+	progress dto.ProgressFunc,
+) error {
+	points := make([]complexity.Point, 0, len(benchmark.Inputs))
+
+	for i, input := range benchmark.Inputs {
+		progress(dto.Progress{Stage: "benchmarking", Test: i + 1, Total: len(benchmark.Inputs)})
+
+		var fastest time.Duration
+		for run := range benchmarkRuns {
+			exec, err := execute(cfg, stdin(sub.ioMode, input.Input))
+			if err != nil {
+				log.Error().Err(err).Int("size", input.Size).Msg("Failed to run benchmark")
+				return fmt.Errorf("failed to run benchmark on size %d: %w", input.Size, err)
+			}
+
+			if exec.Status != "" {
+				if benchmark.MaxComplexity != nil {
+					result.Status = exec.Status
+					result.ErrorMessage = utils.Ptr(fmt.Sprintf("Benchmark of size %d: %s", input.Size, *exec.ErrorMessage))
+					result.Score = 0
+				}
+				return nil
+			}
+
+			if run == 0 || exec.Duration < fastest {
+				fastest = exec.Duration
+			}
+		}
+
+		points = append(points, complexity.Point{Size: input.Size, Duration: fastest})
+	}
+
+	estimated := complexity.Estimate(points)
+	result.Complexity = utils.Ptr(estimated)
+
+	// CLE, complexity limit exceeded, as the tests themselves ran in time
+	if benchmark.MaxComplexity != nil && complexity.Exceeds(estimated, *benchmark.MaxComplexity) {
+		result.Status = "CLE"
+		result.ErrorMessage = utils.Ptr(fmt.Sprintf("Estimated complexity %s, at most %s is accepted", estimated, *benchmark.MaxComplexity))
+		result.Score = 0
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package solver
+
+import (
+	"context"
+)
+
+// boxPool hands out isolate box ids so that concurrent attempts never share a sandbox.
+type boxPool struct {
+	ids chan int
+}
+
+// newBoxPool hands out the ids first..first+size-1.
+func newBoxPool(first, size int) *boxPool {
+	size = max(size, 1)
+
+	ids := make(chan int, size)
+	for id := range size {
+		ids <- first + id
+	}
+
+	return &boxPool{
+		ids: ids,
+	}
+}
+
+func (p *boxPool) acquire(ctx context.Context) (int, error) {
+	select {
+	case id := <-p.ids:
+		return id, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func (p *boxPool) release(id int) {
+	p.ids <- id
+}
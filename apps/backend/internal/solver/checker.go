@@ -0,0 +1,161 @@
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"problum/internal/checker"
+	"problum/internal/solver/dto"
+	testDTO "problum/internal/test/service/dto"
+
+	"github.com/rs/zerolog/log"
+)
+
+// checkerLimits bound a custom checker. It is written by the problem author,
+// but still runs sandboxed in a box of its own.
+var checkerLimits = &dto.Limits{
+	TimeLimit:   10 * time.Second,
+	MemoryLimit: 256 * 1024 * 1024,
+}
+
+// prepareChecker returns the checker configured for the problem together with
+// a function releasing whatever it holds.
+func (s *Solver) prepareChecker(ctx context.Context, ws *workspace, cfg *testDTO.Checker) (checker.Checker, func(), error) {
+	if cfg.Mode != checker.ModeCustom {
+		chk, err := checker.New(cfg.Mode, cfg.Epsilon)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return chk, func() {}, nil
+	}
+
+	runCfg, release, err := s.prepareProgram(ctx, ws, "checker", cfg.Language, cfg.Code, checkerLimits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &customChecker{cfg: runCfg}, release, nil
+}
+
+// prepareProgram builds an author-provided program written in one of the
+// checker languages in its own scratch dir and sets up a box of its own for it.
+func (s *Solver) prepareProgram(
+	ctx context.Context,
+	ws *workspace,
+	name, languageName, code string,
+	limits *dto.Limits,
+) (*runConfig, func(), error) {
+	lang, ok := s.checkers.Get(languageName)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported %s language: %s", name, languageName)
+	}
+
+	dir := filepath.Join(ws.Dir, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s dir: %w", name, err)
+	}
+
+	for _, filename := range lang.Templates() {
+		if err := s.renderTemplate(dir, filename, map[string]any{"code": code}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := lang.Compile(ctx, dir); err != nil {
+		return nil, nil, fmt.Errorf("failed to compile %s: %w", name, err)
+	}
+
+	boxID, err := s.checkerBoxes.acquire(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to acquire %s box: %w", name, err)
+	}
+
+	path, err := s.sandbox.Init(boxID)
+	if err != nil {
+		s.checkerBoxes.release(boxID)
+		return nil, nil, fmt.Errorf("failed to init %s box: %w", name, err)
+	}
+
+	release := func() {
+		s.sandbox.Cleanup(boxID)
+		s.checkerBoxes.release(boxID)
+	}
+
+	runCfg, err := s.getRunConfig(boxID, dir, path, lang, limits)
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("failed to get %s run config: %w", name, err)
+	}
+
+	return runCfg, release, nil
+}
+
+// customChecker runs an author-provided program as
+//
+//	checker input.json output.txt expected.json
+//
+// Exit code 0 accepts the answer and 1 rejects it; whatever the checker prints
+// is shown as the verdict message. Anything else is a failure of the checker.
+type customChecker struct {
+	cfg *runConfig
+}
+
+// checkerFailure is a custom checker crashing, running out of time or exiting
+// with a code of its own. The problem is at fault rather than the judge, so
+// the test is an internal error instead of the attempt being run again.
+type checkerFailure struct {
+	message string
+}
+
+func (e *checkerFailure) Error() string {
+	return e.message
+}
+
+func (c *customChecker) Check(input, expected json.RawMessage, actual []byte) (*checker.Verdict, error) {
+	boxDir := filepath.Dir(c.cfg.StdinFile)
+
+	files := map[string][]byte{
+		"stdin.txt":     input,
+		"input.json":    input,
+		"output.txt":    actual,
+		"expected.json": expected,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(boxDir, name), data, 0o644); err != nil {
+			log.Error().Err(err).Str("file", name).Msg("Failed to write checker file")
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	metadata := run(c.cfg, nil, "input.json", "output.txt", "expected.json")
+
+	stdoutData, _ := readLimited(c.cfg.StdoutFile, maxMessageSize+1)
+	stderrData, _ := readLimited(c.cfg.StderrFile, maxMessageSize+1)
+
+	message := strings.TrimSpace(truncate(stdoutData))
+	if message == "" {
+		message = strings.TrimSpace(truncate(stderrData))
+	}
+
+	switch status := metadata["status"]; status {
+	case "TO", "SG":
+		return nil, &checkerFailure{message: fmt.Sprintf("Checker failed with status %s: %s", status, metadata["message"])}
+	case "XX":
+		return nil, fmt.Errorf("checker failed with status %s: %s", status, metadata["message"])
+	}
+
+	switch exitCode := getExitCode(metadata); exitCode {
+	case 0:
+		return checker.Accepted(), nil
+	case 1:
+		return checker.WrongAnswer(message), nil
+	default:
+		return nil, &checkerFailure{message: fmt.Sprintf("Checker exited with code %d: %s", exitCode, message)}
+	}
+}
@@ -1,15 +1,127 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Result struct {
 	Duration     time.Duration `db:"duration"`
 	MemoryUsage  int64         `db:"memory_usage"`
 	Status       string        `db:"status"`
 	ErrorMessage *string       `db:"error_message"`
+	Score        int           `db:"score"`
+	MaxScore     int           `db:"max_score"`
+	Complexity   *string       `db:"complexity"`
+	// CompileDuration is how long the submission was built, Duration is
+	// only the time of its runs.
+	CompileDuration time.Duration `db:"compile_duration"`
+	TestResults     []TestResult
+}
+
+type TestResult struct {
+	Index          int
+	Subtask        int
+	Status         string
+	Duration       time.Duration
+	MemoryUsage    int64
+	ExitCode       int
+	Public         bool
+	Input          json.RawMessage
+	ExpectedOutput json.RawMessage
+	ActualOutput   *string
+	Stdout         *string
+	ErrorMessage   *string
+}
+
+// Run is code executed on custom inputs, without judging the output.
+type Run struct {
+	ProblemID int
+	UserID    int
+	Language  string
+	Code      string
+	Inputs    []json.RawMessage
+}
+
+type RunResult struct {
+	Status       string
+	ErrorMessage *string
+	Outputs      []RunOutput
+}
+
+// RunOutput holds the returned value of a function submission in Answer,
+// whole programs answer on Stdout.
+type RunOutput struct {
+	Status       string
+	Answer       string
+	Stdout       string
+	Stderr       string
+	Duration     time.Duration
+	MemoryUsage  int64
+	ExitCode     int
+	ErrorMessage *string
+}
+
+// Generate asks for a test for every seed: the generator of the problem
+// prints the input and the reference solution in Language answers it.
+type Generate struct {
+	ProblemID int
+	Language  string
+	Seeds     []int
+}
+
+// GenerateResult is OK with the tests in the order of the seeds, or FAILED
+// with what went wrong when a program written by the author did not work out.
+type GenerateResult struct {
+	Status       string
+	ErrorMessage *string
+	Tests        []GeneratedTest
+}
+
+type GeneratedTest struct {
+	Seed   int
+	Input  json.RawMessage
+	Output json.RawMessage
+}
+
+// Validate asks for the reference solutions of a problem to be judged.
+type Validate struct {
+	ProblemID int
+}
+
+// ValidateResult is OK when the reference solutions could be judged, Passed
+// tells whether every one of them passed within its budget.
+type ValidateResult struct {
+	Status       string
+	Passed       bool
+	ErrorMessage *string
+	Languages    []ValidatedLanguage
+}
+
+// ValidatedLanguage is the verdict on the reference solution in one language.
+// The budgets are half the limits of the problem for that language.
+type ValidatedLanguage struct {
+	Language     string        `json:"language"`
+	Passed       bool          `json:"passed"`
+	Status       string        `json:"status"`
+	ErrorMessage *string       `json:"error_message,omitempty"`
+	Duration     time.Duration `json:"duration"`
+	MemoryUsage  int64         `json:"memory_usage"`
+	TimeBudget   time.Duration `json:"time_budget"`
+	MemoryBudget int64         `json:"memory_budget"`
+	Complexity   *string       `json:"complexity,omitempty"`
 }
 
 type Limits struct {
 	TimeLimit   time.Duration
 	MemoryLimit int64
 }
+
+// Progress reports how far the judging of an attempt has got.
+type Progress struct {
+	Stage string
+	Test  int
+	Total int
+}
+
+type ProgressFunc func(Progress)
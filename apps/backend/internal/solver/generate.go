@@ -0,0 +1,167 @@
+package solver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"problum/internal/language"
+	"problum/internal/model"
+	"problum/internal/solver/dto"
+	testDTO "problum/internal/test/service/dto"
+	"problum/internal/utils"
+
+	"github.com/bytedance/sonic"
+	"github.com/rs/zerolog/log"
+)
+
+// Generate runs the generator of the problem as `generator <seed>` for every
+// seed and answers each input with the reference solution in the requested
+// language, built and sandboxed exactly like attempts in that language.
+// Mistakes of the author are reported as a FAILED result, not as an error.
+func (s *Solver) Generate(ctx context.Context, gen *dto.Generate) (*dto.GenerateResult, error) {
+	generator, err := s.testSvc.GetGenerator(ctx, gen.ProblemID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get generator")
+		return nil, fmt.Errorf("failed to get generator: %w", err)
+	}
+
+	template, err := s.templateSvc.GetByProblemIDAndLanguage(ctx, gen.ProblemID, gen.Language)
+	if err != nil {
+		return generateFailed("there is no %s template for the problem", gen.Language), nil
+	}
+	if template.Reference == nil {
+		return generateFailed("the %s template has no reference solution", gen.Language), nil
+	}
+
+	sub, err := s.prepare(ctx, gen.ProblemID, gen.Language, *template.Reference)
+	if err != nil {
+		return nil, err
+	}
+	if sub.ioMode == model.IOModeInteractive || sub.ioMode == model.IOModeGoTest {
+		return generateFailed("tests of %s problems can not be generated", sub.ioMode), nil
+	}
+
+	ws, err := s.acquireWorkspace(ctx, fmt.Sprintf("generate-%d-", gen.ProblemID))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to acquire workspace")
+		return nil, fmt.Errorf("failed to acquire workspace: %w", err)
+	}
+	defer s.releaseWorkspace(ws)
+
+	inputs, failed, err := s.generateInputs(ctx, ws, generator, sub.ioMode, gen.Seeds)
+	if err != nil || failed != nil {
+		return failed, err
+	}
+
+	result := &dto.GenerateResult{
+		Status: "OK",
+		Tests:  make([]dto.GeneratedTest, 0, len(gen.Seeds)),
+	}
+	stdins := make([][]byte, 0, len(inputs))
+	for i, input := range inputs {
+		result.Tests = append(result.Tests, dto.GeneratedTest{
+			Seed:  gen.Seeds[i],
+			Input: input,
+		})
+		stdins = append(stdins, stdin(sub.ioMode, input))
+	}
+
+	execs, compileOutput, err := s.executeAll(ctx, ws, sub, stdins)
+	if err != nil {
+		return nil, err
+	}
+	if compileOutput != nil {
+		return generateFailed("reference solution does not compile: %s", *compileOutput), nil
+	}
+
+	for i, exec := range execs {
+		seed := gen.Seeds[i]
+		if exec.Status != "" {
+			return generateFailed("reference solution failed on seed %d with %s: %s", seed, exec.Status, *exec.ErrorMessage), nil
+		}
+
+		output, ok := encodeOutput(sub.ioMode, exec.Answer)
+		if !ok {
+			return generateFailed("reference solution printed invalid JSON on seed %d", seed), nil
+		}
+
+		result.Tests[i].Output = output
+	}
+
+	return result, nil
+}
+
+// generateInputs builds the generator in a box of its own and runs it with
+// every seed. A generator that does not work out is reported as a FAILED result.
+func (s *Solver) generateInputs(
+	ctx context.Context,
+	ws *workspace,
+	generator *testDTO.Generator,
+	ioMode string,
+	seeds []int,
+) ([]json.RawMessage, *dto.GenerateResult, error) {
+	cfg, release, err := s.prepareProgram(ctx, ws, "generator", generator.Language, generator.Code, checkerLimits)
+	if err != nil {
+		var compileErr *language.CompileError
+		if errors.As(err, &compileErr) {
+			return nil, generateFailed("generator does not compile: %s", truncate([]byte(compileErr.Output))), nil
+		}
+
+		log.Error().Err(err).Msg("Failed to prepare generator")
+		return nil, nil, fmt.Errorf("failed to prepare generator: %w", err)
+	}
+	defer release()
+
+	inputs := make([]json.RawMessage, 0, len(seeds))
+	for _, seed := range seeds {
+		exec, err := execute(cfg, nil, strconv.Itoa(seed))
+		if err != nil {
+			log.Error().Err(err).Int("seed", seed).Msg("Failed to run generator")
+			return nil, nil, fmt.Errorf("failed to run generator on seed %d: %w", seed, err)
+		}
+		if exec.Status != "" {
+			return nil, generateFailed("generator failed on seed %d with %s: %s", seed, exec.Status, *exec.ErrorMessage), nil
+		}
+
+		input, ok := encodeOutput(ioMode, exec.Stdout)
+		if !ok {
+			return nil, generateFailed("generator printed invalid JSON on seed %d", seed), nil
+		}
+
+		inputs = append(inputs, input)
+	}
+
+	return inputs, nil, nil
+}
+
+// encodeOutput turns what a program printed into the input or output of a
+// test: the JSON itself for harnessed problems, the text as a JSON string for
+// whole programs. It reports false when a harnessed problem got invalid JSON.
+func encodeOutput(ioMode string, stdout []byte) (json.RawMessage, bool) {
+	if !model.Harnessed(ioMode) {
+		text, err := sonic.Marshal(string(stdout))
+		if err != nil {
+			return nil, false
+		}
+
+		return text, true
+	}
+
+	data := bytes.TrimSpace(stdout)
+	if !json.Valid(data) {
+		return nil, false
+	}
+
+	return json.RawMessage(data), true
+}
+
+func generateFailed(format string, args ...any) *dto.GenerateResult {
+	return &dto.GenerateResult{
+		Status:       "FAILED",
+		ErrorMessage: utils.Ptr(fmt.Sprintf(format, args...)),
+	}
+}
@@ -0,0 +1,173 @@
+package solver
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"problum/internal/checker"
+	"problum/internal/solver/dto"
+	testDTO "problum/internal/test/service/dto"
+	"problum/internal/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+// goTestEvent is a line of what go test -json prints, see `go doc test2json`.
+type goTestEvent struct {
+	Action string
+	Test   string
+	Output string
+}
+
+// goTestReport is what the judge package of the harness writes to the answer
+// file once the hidden tests ran: the nonce it was given and their exit code.
+type goTestReport struct {
+	Nonce string `json:"nonce"`
+	Code  int    `json:"code"`
+}
+
+// goPackage returns the package a gotest submission declares, which the
+// harness of the hidden tests is compiled into. Code that does
+// not parse is left to the compiler to report.
+func goPackage(code string) string {
+	file, err := parser.ParseFile(token.NewFileSet(), "solution.go", code, parser.PackageClauseOnly)
+	if err != nil {
+		return "main"
+	}
+
+	return file.Name.Name
+}
+
+// runGoTest runs the hidden test the test case names with the test binary of
+// a gotest submission. Only the report of the harness, tagged with a nonce
+// piped to the run, and the exit code tell how the test went, as stdout and
+// the exit are in the hands of the submission too. The judge package of the
+// harness reads the nonce before any code of the submission runs. A test that
+// passes is accepted, one that fails is a wrong answer with whatever it
+// logged, and a panic, a limit hit, a binary ending without a report or a test
+// that never ran is reported like a failed run. The binary prints what go test
+// -json is made of and test2json turns it into events outside the box, for the
+// logs of the test only.
+func runGoTest(ctx context.Context, cfg *runConfig, test *testDTO.TestCase) (*dto.TestResult, error) {
+	name := checker.Text(test.Input)
+	nonce := rand.Text()
+
+	run, err := executeFrom(cfg, strings.NewReader(nonce), "-test.run", "^"+regexp.QuoteMeta(name)+"$")
+	if err != nil {
+		return nil, err
+	}
+
+	var report goTestReport
+	reported := json.Unmarshal(run.Answer, &report) == nil && report.Nonce == nonce
+
+	result := &dto.TestResult{
+		Status:      "AC",
+		Duration:    run.Duration,
+		MemoryUsage: run.MemoryUsage,
+		ExitCode:    run.ExitCode,
+		Public:      test.Public,
+	}
+	if test.Public {
+		result.Input = test.Input
+		result.ExpectedOutput = test.Output
+	}
+
+	events, err := goTestEvents(ctx, run.Stdout)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to convert test output")
+		return nil, fmt.Errorf("failed to convert test output: %w", err)
+	}
+
+	action, output := goTestOutcome(events, name)
+
+	switch {
+	// the binary exits with 1 when tests fail, anything else is its own failure
+	case reported && report.Code == 1 && run.Status == "RE" && run.ExitCode == 1:
+		result.Status = "WA"
+		result.ErrorMessage = utils.Ptr("Wrong answer")
+		if output != "" {
+			result.ErrorMessage = utils.Ptr(output)
+		}
+	case run.Status != "":
+		result.Status = run.Status
+		result.ErrorMessage = run.ErrorMessage
+	case !reported || report.Code != 0:
+		result.Status = "RE"
+		result.ErrorMessage = utils.Ptr("The tests did not finish")
+	// the report is all the submission can not forge, a stdout closed or
+	// cut short by it leaves nothing to tell the test ran
+	case action == "":
+		result.Status = "RE"
+		result.ErrorMessage = utils.Ptr("The test did not run")
+	}
+
+	return result, nil
+}
+
+// goTestEvents converts the output of a test binary run with
+// -test.v=test2json into events, as go test -json does.
+func goTestEvents(ctx context.Context, stdout []byte) ([]goTestEvent, error) {
+	cmd := exec.CommandContext(ctx, "go", "tool", "test2json")
+	cmd.Stdin = bytes.NewReader(stdout)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run test2json: %w: %s", err, stderr.String())
+	}
+
+	events := make([]goTestEvent, 0)
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var event goTestEvent
+		if err := decoder.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				return events, nil
+			}
+			return nil, fmt.Errorf("failed to decode test event: %w", err)
+		}
+
+		events = append(events, event)
+	}
+}
+
+// goTestOutcome returns the final action of the named test, empty when it
+// never finished, and what the test and its subtests logged on the way.
+func goTestOutcome(events []goTestEvent, name string) (string, string) {
+	var action string
+	var output strings.Builder
+
+	for _, event := range events {
+		if event.Test != name && !strings.HasPrefix(event.Test, name+"/") {
+			continue
+		}
+
+		switch event.Action {
+		case "pass", "fail", "skip":
+			if event.Test == name {
+				action = event.Action
+			}
+		case "output":
+			// the lines go test frames every test with say nothing about it
+			line := strings.TrimSpace(event.Output)
+			if strings.HasPrefix(line, "=== ") || strings.HasPrefix(line, "--- ") {
+				continue
+			}
+			output.WriteString(event.Output)
+		}
+	}
+
+	return action, strings.TrimSpace(truncate([]byte(output.String())))
+}
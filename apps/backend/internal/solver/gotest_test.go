@@ -0,0 +1,153 @@
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"problum/internal/config"
+	"problum/internal/language"
+	"problum/internal/model"
+	testDTO "problum/internal/test/service/dto"
+)
+
+const goTestHidden = `package main
+
+func TestAdd(t *testing.T) {
+	if got := Add(1, 2); got != 3 {
+		t.Fatalf("Add(1, 2) = %d, want 3", got)
+	}
+}`
+
+// goTestForger reads the stdin of the run in init, before the harness runs,
+// to tag a report of its own with the nonce, then prints events of a passing
+// test and ends the binary before the tests run.
+const goTestForger = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"submission/judge"
+)
+
+func init() {
+	os.Stdin.Seek(0, io.SeekStart)
+	nonce, _ := io.ReadAll(os.Stdin)
+
+	report, _ := json.Marshal(map[string]any{"nonce": string(nonce), "code": 0})
+	os.WriteFile("answer.json", report, 0o644)
+	judge.Report(0)
+
+	fmt.Println("=== RUN   TestAdd")
+	fmt.Println("--- PASS: TestAdd (0.00s)")
+	fmt.Println("PASS")
+	os.Exit(0)
+}
+
+func Add(a, b int) int { return a - b }`
+
+// goTestRunConfig builds the test binary of code with the hidden tests in the
+// box of a LocalSandbox, the way the worker builds gotest submissions.
+func goTestRunConfig(t *testing.T, code string) *runConfig {
+	t.Helper()
+
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatalf("config.New failed: %v", err)
+	}
+
+	// the build cache of the worker is not there, the one of the tests is
+	langCfg := *cfg.GoTest["go"]
+	gocache, err := exec.Command("go", "env", "GOCACHE").Output()
+	if err != nil {
+		t.Fatalf("go env failed: %v", err)
+	}
+	langCfg.Env = slices.DeleteFunc(slices.Clone(langCfg.Env), func(env string) bool {
+		return strings.HasPrefix(env, "GOCACHE=")
+	})
+	langCfg.Env = append(langCfg.Env, "GOCACHE="+strings.TrimSpace(string(gocache)))
+
+	lang := language.New("go", &langCfg, &config.Compile{
+		TimeLimit:   2 * time.Minute,
+		MemoryLimit: 4 * 1024 * 1024 * 1024,
+		OutputLimit: 64 * 1024 * 1024,
+	})
+
+	runCfg := localRunConfig(t, "")
+	runCfg.TimeLimit = 5 * time.Second
+	runCfg.RunCommand = lang.RunCommand()
+	setAnswerFile(runCfg, model.IOModeGoTest)
+
+	dir := t.TempDir()
+	metadata := map[string]any{"code": code, "tests": goTestHidden, "package": goPackage(code)}
+	for _, filename := range lang.Templates() {
+		if err := (&Solver{}).renderTemplate(dir, filename, metadata); err != nil {
+			t.Fatalf("renderTemplate(%s) failed: %v", filename, err)
+		}
+	}
+
+	if err := lang.Compile(context.Background(), dir); err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	for _, artifact := range lang.Artifacts() {
+		data, err := os.ReadFile(filepath.Join(dir, artifact))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(filepath.Dir(runCfg.StdinFile), artifact), data, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return runCfg
+}
+
+func TestRunGoTest(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	// the templates and the config file are in the root of the module
+	t.Chdir("../..")
+	t.Setenv("PROBLUM_CONFIG_FILE", "config.yml")
+
+	tests := []struct {
+		name   string
+		code   string
+		status string
+	}{
+		{"passing", "package main\n\nfunc Add(a, b int) int { return a + b }", "AC"},
+		{"failing", "package main\n\nfunc Add(a, b int) int { return a - b }", "WA"},
+		{"panicking", "package main\n\nfunc Add(a, b int) int { panic(\"boom\") }", "RE"},
+		{"forged report", goTestForger, "RE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goTestRunConfig(t, tt.code)
+
+			result, err := runGoTest(context.Background(), cfg, &testDTO.TestCase{Input: json.RawMessage(`"TestAdd"`)})
+			if err != nil {
+				t.Fatalf("runGoTest failed: %v", err)
+			}
+
+			if result.Status != tt.status {
+				message := ""
+				if result.ErrorMessage != nil {
+					message = *result.ErrorMessage
+				}
+				t.Errorf("status = %q (%s), want %q", result.Status, message, tt.status)
+			}
+		})
+	}
+}
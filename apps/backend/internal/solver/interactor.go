@@ -0,0 +1,136 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"problum/internal/checker"
+	"problum/internal/solver/dto"
+	testDTO "problum/internal/test/service/dto"
+	"problum/internal/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+// prepareInteractor builds the interactor of an interactive problem in a box
+// of its own. It gets the time limits of the submission, so that the whole
+// interaction is bounded by them.
+func (s *Solver) prepareInteractor(
+	ctx context.Context,
+	ws *workspace,
+	cfg *testDTO.Checker,
+	limits *dto.Limits,
+) (*runConfig, func(), error) {
+	return s.prepareProgram(ctx, ws, "interactor", cfg.Language, cfg.Code, &dto.Limits{
+		TimeLimit:   limits.TimeLimit,
+		MemoryLimit: checkerLimits.MemoryLimit,
+	})
+}
+
+// interact runs the submission and the interactor in their boxes at the same
+// time, the stdout of each piped into the stdin of the other. The interactor
+// gets the test as
+//
+//	interactor input.txt expected.txt
+//
+// and decides the verdict like a custom checker: exit code 0 accepts, 1
+// rejects with whatever it wrote to stderr, anything else is its own failure.
+func interact(cfg, interactorCfg *runConfig, test *testDTO.TestCase) (*dto.TestResult, error) {
+	boxDir := filepath.Dir(interactorCfg.StdinFile)
+
+	files := map[string][]byte{
+		"input.txt":    []byte(checker.Text(test.Input)),
+		"expected.txt": []byte(checker.Text(test.Output)),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(boxDir, name), data, 0o644); err != nil {
+			log.Error().Err(err).Str("file", name).Msg("Failed to write interactor file")
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	toSolution, fromInteractor, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipe: %w", err)
+	}
+
+	toInteractor, fromSolution, err := os.Pipe()
+	if err != nil {
+		toSolution.Close()
+		fromInteractor.Close()
+		return nil, fmt.Errorf("failed to create pipe: %w", err)
+	}
+
+	solution, solutionErr := cfg.Sandbox.Start(cfg, toSolution, fromSolution)
+	interactor, interactorErr := interactorCfg.Sandbox.Start(interactorCfg, toInteractor, fromInteractor, "input.txt", "expected.txt")
+
+	// the boxes hold their own ends now, closing ours lets each of them see
+	// the end of input once the other one exits
+	for _, f := range []*os.File{toSolution, fromInteractor, toInteractor, fromSolution} {
+		f.Close()
+	}
+
+	if solutionErr == nil {
+		if err := solution.Wait(); err != nil {
+			log.Error().Err(err).Msg("Failed to run solution in sandbox")
+		}
+	}
+	if interactorErr == nil {
+		if err := interactor.Wait(); err != nil {
+			log.Error().Err(err).Msg("Failed to run interactor in sandbox")
+		}
+	}
+
+	if solutionErr != nil || interactorErr != nil {
+		return nil, fmt.Errorf("failed to start interaction: %w", errors.Join(solutionErr, interactorErr))
+	}
+
+	solutionMeta := cfg.Sandbox.Meta(cfg)
+	interactorMeta := interactorCfg.Sandbox.Meta(interactorCfg)
+
+	stderrData, _ := readLimited(cfg.StderrFile, maxMessageSize+1)
+	exec := newExecution(cfg, solutionMeta, nil, stderrData, false)
+
+	result := &dto.TestResult{
+		Status:      "AC",
+		Duration:    exec.Duration,
+		MemoryUsage: exec.MemoryUsage,
+		ExitCode:    exec.ExitCode,
+		Public:      test.Public,
+	}
+	if test.Public {
+		result.Input = test.Input
+		result.ExpectedOutput = test.Output
+	}
+
+	interactorStderrData, _ := readLimited(interactorCfg.StderrFile, maxMessageSize+1)
+	message := strings.TrimSpace(truncate(interactorStderrData))
+
+	_, exited := interactorMeta["exitcode"]
+	switch exitCode := getExitCode(interactorMeta); {
+	case exited && exitCode == 1:
+		// a submission killed by the closed pipe is still just wrong
+		result.Status = "WA"
+		result.ErrorMessage = utils.Ptr("Wrong answer")
+		if message != "" {
+			result.ErrorMessage = utils.Ptr(message)
+		}
+	case exited && exitCode == 0:
+		if exec.Status != "" {
+			result.Status = exec.Status
+			result.ErrorMessage = exec.ErrorMessage
+		}
+	case exec.Status != "":
+		// the interactor was left waiting for a submission that broke
+		result.Status = exec.Status
+		result.ErrorMessage = exec.ErrorMessage
+	default:
+		return nil, fmt.Errorf("interactor failed with status %s: %s", interactorMeta["status"], message)
+	}
+
+	return result, nil
+}
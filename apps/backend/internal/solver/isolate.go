@@ -0,0 +1,145 @@
+package solver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// IsolateSandbox runs programs with the isolate binary, which needs root or
+// its setuid install. It is what the worker judges attempts with.
+type IsolateSandbox struct {
+	cgroup bool
+}
+
+func NewIsolateSandbox(cgroup bool) *IsolateSandbox {
+	return &IsolateSandbox{
+		cgroup: cgroup,
+	}
+}
+
+func (s *IsolateSandbox) Init(boxID int) (string, error) {
+	initCmd := exec.Command("isolate", s.boxArgs(boxID, "--init")...)
+	var initOutput bytes.Buffer
+	var initStderr bytes.Buffer
+	initCmd.Stdout = &initOutput
+	initCmd.Stderr = &initStderr
+
+	if err := initCmd.Run(); err != nil {
+		log.Error().Str("stdout", initOutput.String()).Str("stderr", initStderr.String()).Err(err).Msg("Failed to init isolate")
+		return "", err
+	}
+
+	return initOutput.String(), nil
+}
+
+func (s *IsolateSandbox) Cleanup(boxID int) error {
+	cleanupCmd := exec.Command("isolate", s.boxArgs(boxID, "--cleanup")...)
+	if err := cleanupCmd.Run(); err != nil {
+		log.Error().Err(err).Msg("Failed to cleanup isolate")
+		return fmt.Errorf("failed to cleanup isolate: %w", err)
+	}
+
+	return nil
+}
+
+func (s *IsolateSandbox) boxArgs(boxID int, args ...string) []string {
+	boxArgs := []string{"--box-id", strconv.Itoa(boxID)}
+	if s.cgroup {
+		boxArgs = append(boxArgs, "--cg")
+	}
+
+	return append(boxArgs, args...)
+}
+
+// Start leaves the streams that are given to isolate itself, so that they
+// can be connected to another box.
+func (s *IsolateSandbox) Start(cfg *runConfig, stdin io.Reader, stdout io.Writer, args ...string) (Process, error) {
+	streams := []string{"--stderr", "stderr.txt"}
+	if stdin == nil {
+		streams = append(streams, "--stdin", "stdin.txt")
+	}
+	if stdout == nil {
+		streams = append(streams, "--stdout", "stdout.txt")
+	}
+
+	process := &isolateProcess{
+		cmd: exec.Command("isolate", isolateArgs(cfg, streams, args...)...),
+	}
+	process.cmd.Stdin = stdin
+	process.cmd.Stdout = stdout
+	if stdout == nil {
+		process.cmd.Stdout = &process.output
+	}
+	process.cmd.Stderr = &process.output
+
+	if err := process.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start isolate: %w", err)
+	}
+
+	return process, nil
+}
+
+func (s *IsolateSandbox) Meta(cfg *runConfig) map[string]string {
+	return readMetaFile(cfg)
+}
+
+// isolateArgs is the command line running the configured command in the box
+// with its streams redirected as given, appending args to the command.
+func isolateArgs(cfg *runConfig, streams []string, args ...string) []string {
+	isolateArgs := []string{
+		"--box-id", strconv.Itoa(cfg.BoxID),
+		"--meta", cfg.MetaFile,
+	}
+	isolateArgs = append(isolateArgs, streams...)
+	isolateArgs = append(isolateArgs,
+		"--time", formatSeconds(cfg.TimeLimit),
+		"--wall-time", formatSeconds(cfg.TimeLimit),
+	)
+	mem := strconv.FormatInt(toKiloBytes(cfg.SandboxMemory), 10)
+	if cfg.Cgroup {
+		isolateArgs = append(isolateArgs, "--cg", "--cg-mem", mem)
+	} else {
+		isolateArgs = append(isolateArgs, "--mem", mem)
+	}
+	if cfg.OutputLimit > 0 {
+		isolateArgs = append(isolateArgs, "--fsize", strconv.FormatInt(max(toKiloBytes(cfg.OutputLimit), 1), 10))
+	}
+	if cfg.Processes > 1 {
+		isolateArgs = append(isolateArgs, fmt.Sprintf("--processes=%d", cfg.Processes))
+	}
+	isolateArgs = append(isolateArgs, "--run", "--")
+	isolateArgs = append(isolateArgs, cfg.RunCommand...)
+
+	return append(isolateArgs, args...)
+}
+
+type isolateProcess struct {
+	cmd    *exec.Cmd
+	output bytes.Buffer
+}
+
+// Wait takes exit code 1 of isolate for what it is, the program failing.
+func (p *isolateProcess) Wait() error {
+	err := p.cmd.Wait()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("isolate failed: %w: %s", err, p.output.String())
+	}
+
+	return nil
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,263 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LocalSandbox runs programs as the user of the worker, bounded by rlimits
+// and, where the kernel lets unprivileged users have them, kept off the
+// network and the other processes by namespaces. It needs neither root nor
+// isolate, so it is there for development and tests. It is no match for
+// isolate: the file system is shared, there are no control groups and the
+// number of processes is not bounded.
+type LocalSandbox struct {
+	root string
+	// noNamespaces is set once the kernel refused them, runs go on without.
+	noNamespaces atomic.Bool
+}
+
+func NewLocalSandbox(root string) *LocalSandbox {
+	return &LocalSandbox{
+		root: root,
+	}
+}
+
+func (s *LocalSandbox) path(boxID int) string {
+	return filepath.Join(s.root, strconv.Itoa(boxID))
+}
+
+// Init starts the box afresh, like isolate does.
+func (s *LocalSandbox) Init(boxID int) (string, error) {
+	path := s.path(boxID)
+	if err := os.RemoveAll(path); err != nil {
+		return "", fmt.Errorf("failed to remove box: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(path, "box"), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create box: %w", err)
+	}
+
+	return path, nil
+}
+
+func (s *LocalSandbox) Cleanup(boxID int) error {
+	if err := os.RemoveAll(s.path(boxID)); err != nil {
+		log.Error().Err(err).Msg("Failed to cleanup box")
+		return fmt.Errorf("failed to cleanup box: %w", err)
+	}
+
+	return nil
+}
+
+func (s *LocalSandbox) Meta(cfg *runConfig) map[string]string {
+	return readMetaFile(cfg)
+}
+
+// Start has a shell set the rlimits and replace itself with the command, as
+// os/exec can not set them between fork and exec. The CPU limit is rounded
+// up to whole seconds there, so the exact one is checked once the run is over.
+func (s *LocalSandbox) Start(cfg *runConfig, stdin io.Reader, stdout io.Writer, args ...string) (Process, error) {
+	// POSIX shells take one limit at a time and count the file size in
+	// blocks of 512 bytes
+	limits := []string{"ulimit -t " + strconv.Itoa(int(math.Ceil(cfg.TimeLimit.Seconds())))}
+	if cfg.SandboxMemory > 0 {
+		limits = append(limits, "ulimit -v "+strconv.FormatInt(toKiloBytes(cfg.SandboxMemory), 10))
+	}
+	if cfg.OutputLimit > 0 {
+		limits = append(limits, "ulimit -f "+strconv.FormatInt(max(cfg.OutputLimit/512, 1), 10))
+	}
+
+	script := strings.Join(limits, " && ") + ` && exec "$@"`
+	dir := filepath.Dir(cfg.StdinFile)
+	command := func(namespaces bool) *exec.Cmd {
+		cmd := exec.Command("/bin/sh", append(append([]string{"-c", script, "sh"}, cfg.RunCommand...), args...)...)
+		cmd.Dir = dir
+		cmd.Env = []string{"PATH=/usr/local/bin:/usr/bin:/bin", "HOME=" + dir}
+		cmd.SysProcAttr = localSysProcAttr(namespaces)
+
+		return cmd
+	}
+
+	process := &localProcess{
+		cfg: cfg,
+		cmd: command(!s.noNamespaces.Load()),
+	}
+	if err := process.redirect(stdin, stdout); err != nil {
+		process.closeFiles()
+		return nil, err
+	}
+
+	err := s.start(process)
+	if err != nil && !s.noNamespaces.Load() && (errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOSPC)) {
+		log.Warn().Err(err).Msg("Namespaces are not available, running without them")
+		s.noNamespaces.Store(true)
+
+		// a command is not started twice
+		cmd := command(false)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = process.cmd.Stdin, process.cmd.Stdout, process.cmd.Stderr
+		process.cmd = cmd
+		err = s.start(process)
+	}
+	if err != nil {
+		process.closeFiles()
+		return nil, fmt.Errorf("failed to start program: %w", err)
+	}
+
+	return process, nil
+}
+
+func (s *LocalSandbox) start(process *localProcess) error {
+	process.started = time.Now()
+	if err := process.cmd.Start(); err != nil {
+		return err
+	}
+
+	// the whole process group goes once the wall time is up
+	process.timer = time.AfterFunc(process.cfg.TimeLimit, func() {
+		process.timedOut.Store(true)
+		syscall.Kill(-process.cmd.Process.Pid, syscall.SIGKILL)
+	})
+
+	return nil
+}
+
+type localProcess struct {
+	cfg      *runConfig
+	cmd      *exec.Cmd
+	files    []*os.File
+	started  time.Time
+	timer    *time.Timer
+	timedOut atomic.Bool
+	waitOnce sync.Once
+}
+
+// redirect connects the streams that are not given to the files of the box.
+func (p *localProcess) redirect(stdin io.Reader, stdout io.Writer) error {
+	open := func(name string, flag int) (*os.File, error) {
+		f, err := os.OpenFile(filepath.Join(p.cmd.Dir, name), flag, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		p.files = append(p.files, f)
+
+		return f, nil
+	}
+
+	p.cmd.Stdin = stdin
+	if stdin == nil {
+		f, err := open("stdin.txt", os.O_RDONLY|os.O_CREATE)
+		if err != nil {
+			return err
+		}
+		p.cmd.Stdin = f
+	}
+
+	p.cmd.Stdout = stdout
+	if stdout == nil {
+		f, err := open("stdout.txt", os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+		if err != nil {
+			return err
+		}
+		p.cmd.Stdout = f
+	}
+
+	f, err := open("stderr.txt", os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	p.cmd.Stderr = f
+
+	return nil
+}
+
+func (p *localProcess) closeFiles() {
+	for _, f := range p.files {
+		f.Close()
+	}
+}
+
+// Wait records the run in the meta file with the fields and statuses
+// isolate uses.
+func (p *localProcess) Wait() error {
+	var err error
+	p.waitOnce.Do(func() {
+		err = p.wait()
+	})
+
+	return err
+}
+
+func (p *localProcess) wait() error {
+	waitErr := p.cmd.Wait()
+	wall := time.Since(p.started)
+	p.timer.Stop()
+	p.closeFiles()
+
+	// whatever the program left running in its group goes with it
+	syscall.Kill(-p.cmd.Process.Pid, syscall.SIGKILL)
+
+	var exitErr *exec.ExitError
+	if waitErr != nil && !errors.As(waitErr, &exitErr) {
+		return fmt.Errorf("failed to wait for program: %w", waitErr)
+	}
+
+	state := p.cmd.ProcessState
+	cpu := state.UserTime() + state.SystemTime()
+
+	meta := map[string]string{
+		"time":      formatSeconds(cpu),
+		"time-wall": formatSeconds(wall),
+	}
+	if rusage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		meta["max-rss"] = strconv.FormatInt(maxRSSKiloBytes(rusage), 10)
+	}
+
+	status, _ := state.Sys().(syscall.WaitStatus)
+	switch {
+	case p.timedOut.Load():
+		meta["status"] = "TO"
+		meta["killed"] = "1"
+		meta["message"] = "Time limit exceeded (wall clock)"
+	case cpu > p.cfg.TimeLimit || (status.Signaled() && status.Signal() == syscall.SIGXCPU):
+		meta["status"] = "TO"
+		meta["killed"] = "1"
+		meta["message"] = "Time limit exceeded"
+	case status.Signaled():
+		meta["status"] = "SG"
+		meta["exitsig"] = strconv.Itoa(int(status.Signal()))
+		meta["message"] = fmt.Sprintf("Caught fatal signal %d", status.Signal())
+	default:
+		meta["exitcode"] = strconv.Itoa(state.ExitCode())
+		if state.ExitCode() != 0 {
+			meta["status"] = "RE"
+			meta["message"] = fmt.Sprintf("Exited with error status %d", state.ExitCode())
+		}
+	}
+
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(meta)) {
+		fmt.Fprintf(&b, "%s:%s\n", key, meta[key])
+	}
+
+	if err := os.WriteFile(p.cfg.MetaFile, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write meta file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package solver
+
+import (
+	"os"
+	"syscall"
+)
+
+// localSysProcAttr puts the program in a process group of its own and, with
+// namespaces, in user, network, IPC and UTS namespaces of its own. It keeps
+// the PID namespace, whose first process would not get the signals the
+// rlimits send.
+func localSysProcAttr(namespaces bool) *syscall.SysProcAttr {
+	attr := &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGKILL,
+	}
+	if !namespaces {
+		return attr
+	}
+
+	attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+	attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}}
+	attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}}
+	attr.GidMappingsEnableSetgroups = false
+
+	return attr
+}
+
+// maxRSSKiloBytes reads the peak memory, Linux reports it in kilobytes.
+func maxRSSKiloBytes(rusage *syscall.Rusage) int64 {
+	return rusage.Maxrss
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package solver
+
+import "syscall"
+
+// localSysProcAttr only puts the program in a process group of its own, there
+// are no namespaces outside Linux.
+func localSysProcAttr(bool) *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+}
+
+// maxRSSKiloBytes reads the peak memory, BSDs and macOS report it in bytes.
+func maxRSSKiloBytes(rusage *syscall.Rusage) int64 {
+	return int64(rusage.Maxrss) / 1024
+}
@@ -0,0 +1,45 @@
+package solver
+
+import (
+	"io"
+	"os"
+)
+
+// maxMessageSize bounds the stderr, messages and output stored with a result.
+const maxMessageSize = 64 * 1024
+
+const truncatedMarker = "\n... (truncated)"
+
+// sigxfsz is the signal a program gets when it writes past the output limit.
+const sigxfsz = "25"
+
+// readLimited reads at most limit bytes of the file and reports whether it
+// holds more. A non-positive limit reads the whole file.
+func readLimited(path string, limit int64) ([]byte, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	if limit <= 0 {
+		data, _ := io.ReadAll(f)
+		return data, false
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(f, limit+1))
+	if int64(len(data)) > limit {
+		return data[:limit], true
+	}
+
+	return data, false
+}
+
+// truncate cuts data down to maxMessageSize, marking that it was cut.
+func truncate(data []byte) string {
+	if len(data) <= maxMessageSize {
+		return string(data)
+	}
+
+	return string(data[:maxMessageSize]) + truncatedMarker
+}
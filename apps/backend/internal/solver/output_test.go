@@ -0,0 +1,59 @@
+package solver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadLimited(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stdout.txt")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		limit    int64
+		want     string
+		exceeded bool
+	}{
+		{"no limit", path, 0, "0123456789", false},
+		{"under the limit", path, 20, "0123456789", false},
+		{"at the limit", path, 10, "0123456789", false},
+		{"over the limit", path, 4, "0123", true},
+		{"missing file", filepath.Join(t.TempDir(), "missing.txt"), 10, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, exceeded := readLimited(tt.path, tt.limit)
+			if string(data) != tt.want || exceeded != tt.exceeded {
+				t.Errorf("readLimited(%d) = %q, %v, want %q, %v", tt.limit, data, exceeded, tt.want, tt.exceeded)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"empty", "", ""},
+		{"short", "Wrong answer", "Wrong answer"},
+		{"at the limit", strings.Repeat("a", maxMessageSize), strings.Repeat("a", maxMessageSize)},
+		{"over the limit", strings.Repeat("a", maxMessageSize+1), strings.Repeat("a", maxMessageSize) + truncatedMarker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncate([]byte(tt.data)); got != tt.want {
+				t.Errorf("truncate() gave %d bytes, want %d", len(got), len(tt.want))
+			}
+		})
+	}
+}
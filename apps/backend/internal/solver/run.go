@@ -0,0 +1,112 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+
+	"problum/internal/model"
+	"problum/internal/solver/dto"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Run builds the code like Solve does and executes it on every custom input.
+// The output is returned as is, nothing is compared.
+func (s *Solver) Run(ctx context.Context, run *dto.Run) (*dto.RunResult, error) {
+	sub, err := s.prepare(ctx, run.ProblemID, run.Language, run.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := s.acquireWorkspace(ctx, fmt.Sprintf("run-%d-", run.UserID))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to acquire workspace")
+		return nil, fmt.Errorf("failed to acquire workspace: %w", err)
+	}
+	defer s.releaseWorkspace(ws)
+
+	inputs := make([][]byte, 0, len(run.Inputs))
+	for _, input := range run.Inputs {
+		inputs = append(inputs, stdin(sub.ioMode, input))
+	}
+
+	execs, compileOutput, err := s.executeAll(ctx, ws, sub, inputs)
+	if err != nil {
+		return nil, err
+	}
+	if compileOutput != nil {
+		return &dto.RunResult{
+			Status:       "CE",
+			ErrorMessage: compileOutput,
+		}, nil
+	}
+
+	result := &dto.RunResult{
+		Status:  "OK",
+		Outputs: make([]dto.RunOutput, 0, len(execs)),
+	}
+
+	for _, exec := range execs {
+		status := exec.Status
+		if status == "" {
+			status = "OK"
+		}
+
+		output := dto.RunOutput{
+			Status:       status,
+			Stdout:       truncate(exec.Stdout),
+			Stderr:       truncate(exec.Stderr),
+			Duration:     exec.Duration,
+			MemoryUsage:  exec.MemoryUsage,
+			ExitCode:     exec.ExitCode,
+			ErrorMessage: exec.ErrorMessage,
+		}
+		if model.Harnessed(sub.ioMode) {
+			output.Answer = truncate(exec.Answer)
+		}
+
+		result.Outputs = append(result.Outputs, output)
+	}
+
+	return result, nil
+}
+
+// executeAll builds the submission and executes it on every input in the box
+// of the workspace. When the submission does not compile, the compiler output
+// is returned instead of an error.
+func (s *Solver) executeAll(ctx context.Context, ws *workspace, sub *submission, inputs [][]byte) ([]*execution, *string, error) {
+	compileOutput, err := s.build(ctx, ws, sub)
+	if err != nil {
+		return nil, nil, err
+	}
+	if compileOutput != nil {
+		return nil, compileOutput, nil
+	}
+
+	path, err := s.sandbox.Init(ws.BoxID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to init sandbox")
+		return nil, nil, fmt.Errorf("failed to init sandbox: %w", err)
+	}
+	defer s.sandbox.Cleanup(ws.BoxID)
+
+	cfg, err := s.getRunConfig(ws.BoxID, ws.Dir, path, sub.lang, sub.limits)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get run config")
+		return nil, nil, fmt.Errorf("failed to get run config: %w", err)
+	}
+	setAnswerFile(cfg, sub.ioMode)
+
+	execs := make([]*execution, 0, len(inputs))
+	for i, input := range inputs {
+		exec, err := execute(cfg, input)
+		if err != nil {
+			log.Error().Err(err).Int("input", i).Msg("Failed to run input")
+			return nil, nil, fmt.Errorf("failed to run input %d: %w", i, err)
+		}
+
+		execs = append(execs, exec)
+	}
+
+	return execs, nil, nil
+}
@@ -0,0 +1,47 @@
+package solver
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"problum/internal/config"
+)
+
+// Sandbox runs programs in numbered boxes under the limits of a runConfig.
+// Whatever it runs them with, it records how a run went in the meta file of
+// the box the way isolate does, see `man isolate`.
+type Sandbox interface {
+	// Init sets up the box and returns its path. Programs run in the box
+	// directory under it, next to which the meta file is kept.
+	Init(boxID int) (string, error)
+	// Start runs the configured command in its box, appending args to it.
+	// Nil stdin and stdout are the stdin.txt and stdout.txt files of the box,
+	// stderr always goes to stderr.txt.
+	Start(cfg *runConfig, stdin io.Reader, stdout io.Writer, args ...string) (Process, error)
+	// Meta returns what was recorded about the last run in the box.
+	Meta(cfg *runConfig) map[string]string
+	// Cleanup removes the box and whatever was left in it.
+	Cleanup(boxID int) error
+}
+
+// Process is a program started in a sandbox.
+type Process interface {
+	// Wait waits for the program to finish. How it finished is in the meta
+	// file, an error means the sandbox itself failed.
+	Wait() error
+}
+
+func newSandbox(cfg *config.Worker) Sandbox {
+	if cfg.Sandbox == config.SandboxLocal {
+		return NewLocalSandbox(filepath.Join(cfg.WorkDir, "boxes"))
+	}
+
+	return NewIsolateSandbox(cfg.Cgroup)
+}
+
+func readMetaFile(cfg *runConfig) map[string]string {
+	metaData, _ := os.ReadFile(cfg.MetaFile)
+
+	return parseMetadata(metaData)
+}
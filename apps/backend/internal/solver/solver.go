@@ -2,19 +2,22 @@ package solver
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	attemptDTO "problum/internal/attempt/service/dto"
+	"problum/internal/checker"
+	"problum/internal/config"
+	"problum/internal/language"
+	"problum/internal/model"
 	"problum/internal/solver/dto"
 	templateDTO "problum/internal/template/service/dto"
 	testDTO "problum/internal/test/service/dto"
@@ -26,75 +29,186 @@ import (
 	"github.com/bytedance/sonic"
 	"github.com/flosch/pongo2/v6"
 	"github.com/rs/zerolog/log"
-	"golang.org/x/tools/imports"
-)
-
-var (
-	errWrongAnswer    = errors.New("wrong answer")
-	errNotNilExitCode = errors.New("not nil exit code")
 )
 
 type TestService interface {
 	GetByProblemID(context.Context, int) (*testDTO.Test, error)
+	GetGenerator(context.Context, int) (*testDTO.Generator, error)
+	GetBenchmark(context.Context, int) (*testDTO.Benchmark, error)
 }
 
 type TemplateService interface {
 	GetByProblemIDAndLanguage(context.Context, int, string) (*templateDTO.Template, error)
+	GetLanguagesByProblemID(context.Context, int) ([]string, error)
 }
 
 type ProblemService interface {
 	GetWithOptions(context.Context, int, ...problemSvc.Option) (*problemDTO.Problem, error)
 }
 
+type LanguageRegistry interface {
+	Get(string) (language.Language, bool)
+	Warm(context.Context) error
+}
+
 type Solver struct {
-	testSvc     TestService
-	templateSvc TemplateService
-	problemSvc  ProblemService
+	cfg          *config.Worker
+	languages    LanguageRegistry
+	stdio        LanguageRegistry
+	goTest       LanguageRegistry
+	design       LanguageRegistry
+	checkers     LanguageRegistry
+	testSvc      TestService
+	templateSvc  TemplateService
+	problemSvc   ProblemService
+	sandbox      Sandbox
+	boxes        *boxPool
+	checkerBoxes *boxPool
+}
+
+// workspace is the isolate box and the scratch directory owned by a single attempt.
+type workspace struct {
+	BoxID int
+	Dir   string
 }
 
-type runIsolateConfig struct {
+// runConfig is how a program is run in a box of the sandbox.
+type runConfig struct {
+	Sandbox    Sandbox
+	BoxID      int
 	StdinFile  string
 	StdoutFile string
 	StderrFile string
 	MetaFile   string
-	BoxFile    string
-	Time       string
-	WallTime   string
-	Mem        string
-	Processes  string
+	TimeLimit  time.Duration
+	// SandboxMemory is what the sandbox bounds: the memory of the control
+	// group when there is one, otherwise the address space of every process.
+	SandboxMemory int64
+	MemoryLimit   int64
+	// Cgroup runs the box in its own control group, see config.Worker.Cgroup.
+	Cgroup      bool
+	OutputLimit int64
+	// AnswerFile is where the harnesses write the returned value,
+	// empty for whole programs answering on stdout.
+	AnswerFile string
+	Processes  int
 	RunCommand []string
 }
 
-func New(testSvc TestService, templateSvc TemplateService, problemSvc ProblemService) *Solver {
+func New(
+	cfg *config.Worker,
+	languages LanguageRegistry,
+	stdio LanguageRegistry,
+	goTest LanguageRegistry,
+	design LanguageRegistry,
+	checkers LanguageRegistry,
+	testSvc TestService,
+	templateSvc TemplateService,
+	problemSvc ProblemService,
+) *Solver {
+	concurrency := max(cfg.Concurrency, 1)
+
 	return &Solver{
-		testSvc:     testSvc,
-		templateSvc: templateSvc,
-		problemSvc:  problemSvc,
+		cfg:          cfg,
+		languages:    languages,
+		stdio:        stdio,
+		goTest:       goTest,
+		design:       design,
+		checkers:     checkers,
+		testSvc:      testSvc,
+		templateSvc:  templateSvc,
+		problemSvc:   problemSvc,
+		sandbox:      newSandbox(cfg),
+		boxes:        newBoxPool(0, concurrency),
+		checkerBoxes: newBoxPool(concurrency, concurrency),
+	}
+}
+
+// Warm fills the compiler caches of the languages of every registry, so that
+// the first submissions are not the ones compiling the standard libraries.
+func (s *Solver) Warm(ctx context.Context) error {
+	var errs []error
+	for _, registry := range []LanguageRegistry{s.languages, s.stdio, s.goTest, s.design, s.checkers} {
+		if err := registry.Warm(ctx); err != nil {
+			errs = append(errs, err)
+		}
 	}
+
+	return errors.Join(errs...)
 }
 
-func (s *Solver) Solve(ctx context.Context, attempt *attemptDTO.Attempt) (*dto.Result, error) {
+// Solve judges the attempt, reporting its stages and tests to progress as it goes.
+func (s *Solver) Solve(ctx context.Context, attempt *attemptDTO.Attempt, progress dto.ProgressFunc) (*dto.Result, error) {
+	if progress == nil {
+		progress = func(dto.Progress) {}
+	}
+
 	test, err := s.testSvc.GetByProblemID(ctx, attempt.ProblemID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get test for problem")
 		return nil, fmt.Errorf("failed to get test for problem: %w", err)
 	}
 
-	template, err := s.templateSvc.GetByProblemIDAndLanguage(ctx, attempt.ProblemID, attempt.Language)
+	benchmark, err := s.getBenchmark(ctx, attempt.ProblemID)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get template for problem")
-		return nil, fmt.Errorf("failed to get template for problem: %w", err)
+		return nil, err
+	}
+
+	sub, err := s.prepare(ctx, attempt.ProblemID, attempt.Language, attempt.Code)
+	if err != nil {
+		return nil, err
 	}
 
-	problem, err := s.problemSvc.GetWithOptions(ctx, test.ProblemID)
+	ws, err := s.acquireWorkspace(ctx, fmt.Sprintf("attempt-%d-", attempt.ID))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to acquire workspace")
+		return nil, fmt.Errorf("failed to acquire workspace: %w", err)
+	}
+	defer s.releaseWorkspace(ws)
+
+	return s.solve(ctx, ws, sub, test, benchmark, progress)
+}
+
+// submission is everything needed to build and sandbox code written for a problem.
+type submission struct {
+	lang     language.Language
+	ioMode   string
+	metadata map[string]any
+	limits   *dto.Limits
+}
+
+func (s *Solver) prepare(ctx context.Context, problemID int, languageName, code string) (*submission, error) {
+	problem, err := s.problemSvc.GetWithOptions(ctx, problemID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get problem")
 		return nil, fmt.Errorf("failed to get problem")
 	}
 
-	limits := &dto.Limits{
-		TimeLimit:   problem.TimeLimit / time.Second,
-		MemoryLimit: problem.MemoryLimit,
+	// stdio and interactive submissions are whole programs, gotest ones are
+	// packages built together with the hidden tests and design ones are
+	// classes with a harness of their own
+	var languages LanguageRegistry
+	switch problem.IOMode {
+	case model.IOModeFunction:
+		languages = s.languages
+	case model.IOModeGoTest:
+		languages = s.goTest
+	case model.IOModeDesign:
+		languages = s.design
+	default:
+		languages = s.stdio
+	}
+
+	lang, ok := languages.Get(languageName)
+	if !ok {
+		log.Error().Str("language", languageName).Str("io_mode", problem.IOMode).Msg("Unsupported language")
+		return nil, fmt.Errorf("unsupported language: %s", languageName)
+	}
+
+	template, err := s.templateSvc.GetByProblemIDAndLanguage(ctx, problemID, languageName)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get template for problem")
+		return nil, fmt.Errorf("failed to get template for problem: %w", err)
 	}
 
 	metadata, err := parseTemplateMetadata(template.Metadata)
@@ -102,124 +216,194 @@ func (s *Solver) Solve(ctx context.Context, attempt *attemptDTO.Attempt) (*dto.R
 		log.Error().Err(err).Msg("Failed to parse template metadata")
 		return nil, fmt.Errorf("failed to parse template metadata: %w", err)
 	}
-	metadata["code"] = attempt.Code
-	log.Info().Interface("metadata", metadata).Msg("metadata")
+	metadata["code"] = code
+
+	return &submission{
+		lang:     lang,
+		ioMode:   problem.IOMode,
+		metadata: metadata,
+		limits: &dto.Limits{
+			TimeLimit:   problem.TimeLimit,
+			MemoryLimit: problem.MemoryLimit,
+		},
+	}, nil
+}
 
-	switch attempt.Language {
-	case "python":
-		return s.solvePython(test, metadata, limits)
-	case "go":
-		return s.solveGolang(test, metadata, limits)
-	default:
-		log.Error().Err(err).Msg("Failed to get test for problem")
-		return nil, fmt.Errorf("unsupported language")
+func (s *Solver) solve(
+	ctx context.Context,
+	ws *workspace,
+	sub *submission,
+	test *testDTO.Test,
+	benchmark *testDTO.Benchmark,
+	progress dto.ProgressFunc,
+) (*dto.Result, error) {
+	result := &dto.Result{
+		MaxScore: test.MaxScore(),
 	}
-}
 
-func (s *Solver) solvePython(test *testDTO.Test, md map[string]any, limits *dto.Limits) (*dto.Result, error) {
-	result := &dto.Result{}
+	progress(dto.Progress{Stage: "compiling"})
 
-	if err := s.renderTemplate("code.py.j2", md); err != nil {
+	if sub.ioMode == model.IOModeGoTest {
+		sub.metadata["tests"] = test.Checker.Code
+		code, _ := sub.metadata["code"].(string)
+		sub.metadata["package"] = goPackage(code)
+	}
+
+	compileStarted := time.Now()
+	compileOutput, err := s.build(ctx, ws, sub)
+	if err != nil {
 		return nil, err
 	}
+	result.CompileDuration = time.Since(compileStarted)
+	if compileOutput != nil {
+		result.Status = "CE"
+		result.ErrorMessage = compileOutput
+		return result, nil
+	}
 
-	path, err := initIsolate()
+	path, err := s.sandbox.Init(ws.BoxID)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to init isolate")
-		return nil, fmt.Errorf("failed to init isolate: %w", err)
+		log.Error().Err(err).Msg("Failed to init sandbox")
+		return nil, fmt.Errorf("failed to init sandbox: %w", err)
 	}
+	defer s.sandbox.Cleanup(ws.BoxID)
 
-	if err := runTests(path, "python", test.Tests, result, limits); err != nil {
+	cfg, err := s.getRunConfig(ws.BoxID, ws.Dir, path, sub.lang, sub.limits)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get run config")
+		return nil, fmt.Errorf("failed to get run config: %w", err)
+	}
+	setAnswerFile(cfg, sub.ioMode)
+
+	judge, releaseJudge, err := s.prepareJudge(ctx, ws, sub, cfg, &test.Checker)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to prepare judge")
+		return nil, fmt.Errorf("failed to prepare judge: %w", err)
+	}
+	defer releaseJudge()
+
+	if err := s.runTests(test, judge, result, progress); err != nil {
 		log.Error().Err(err).Msg("Failed to run tests")
 		return nil, fmt.Errorf("failed to run tests: %w", err)
 	}
 
-	if err := cleanupIsolate(); err != nil {
-		log.Error().Err(err).Msg("Failed to cleanup isolate")
-		return nil, fmt.Errorf("failed to cleanup isolate: %w", err)
+	// only an accepted submission is worth timing, the others failed already
+	if benchmark != nil && result.Status == "AC" {
+		if err := runBenchmark(cfg, sub, benchmark, result, progress); err != nil {
+			return nil, err
+		}
 	}
 
 	return result, nil
 }
 
-func (s *Solver) solveGolang(test *testDTO.Test, md map[string]any, limits *dto.Limits) (*dto.Result, error) {
-	result := &dto.Result{}
-
-	if err := s.renderTemplate("code.go.j2", md); err != nil {
-		return nil, err
-	}
+// judgeFunc runs the built submission on a single test and judges it.
+type judgeFunc func(*testDTO.TestCase) (*dto.TestResult, error)
+
+// prepareJudge returns how tests of the problem are judged, together with a
+// function releasing whatever it holds. Interactive problems talk to their
+// interactor, gotest ones run the hidden test each test names and the others
+// have the output of each run checked.
+func (s *Solver) prepareJudge(
+	ctx context.Context,
+	ws *workspace,
+	sub *submission,
+	cfg *runConfig,
+	chkCfg *testDTO.Checker,
+) (judgeFunc, func(), error) {
+	if sub.ioMode == model.IOModeInteractive {
+		interactorCfg, release, err := s.prepareInteractor(ctx, ws, chkCfg, sub.limits)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	if err := s.renderTemplate("harness.go.j2", md); err != nil {
-		return nil, err
+		return func(t *testDTO.TestCase) (*dto.TestResult, error) {
+			return interact(cfg, interactorCfg, t)
+		}, release, nil
 	}
 
-	if errorMsg, err := s.compileGolang(); err != nil {
-		result.Status = "CE"
-		if errorMsg != nil {
-			result.ErrorMessage = errorMsg
-		} else {
-			result.ErrorMessage = utils.Ptr("Compile error")
-		}
-
-		return result, nil
+	if sub.ioMode == model.IOModeGoTest {
+		return func(t *testDTO.TestCase) (*dto.TestResult, error) {
+			return runGoTest(ctx, cfg, t)
+		}, func() {}, nil
 	}
 
-	path, err := initIsolate()
+	chk, release, err := s.prepareChecker(ctx, ws, chkCfg)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to init isolate")
-		return nil, fmt.Errorf("failed to init isolate: %w", err)
+		return nil, nil, err
 	}
 
-	if err := runTests(path, "go", test.Tests, result, limits); err != nil {
-		log.Error().Err(err).Msg("Failed to run tests")
-		return nil, fmt.Errorf("failed to run tests: %w", err)
+	return func(t *testDTO.TestCase) (*dto.TestResult, error) {
+		return runTest(cfg, t, stdin(sub.ioMode, t.Input), chk)
+	}, release, nil
+}
+
+// build renders and compiles the submission in the scratch dir. When the
+// submission does not compile, the compiler output is returned instead of an error.
+func (s *Solver) build(ctx context.Context, ws *workspace, sub *submission) (*string, error) {
+	for _, filename := range sub.lang.Templates() {
+		if err := s.renderTemplate(ws.Dir, filename, sub.metadata); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := cleanupIsolate(); err != nil {
-		log.Error().Err(err).Msg("Failed to cleanup isolate")
-		return nil, fmt.Errorf("failed to cleanup isolate: %w", err)
+	if err := sub.lang.Compile(ctx, ws.Dir); err != nil {
+		var compileErr *language.CompileError
+		if !errors.As(err, &compileErr) {
+			log.Error().Err(err).Msg("Failed to compile")
+			return nil, fmt.Errorf("failed to compile: %w", err)
+		}
+
+		if compileErr.Output == "" {
+			return utils.Ptr("Compile error"), nil
+		}
+
+		return utils.Ptr(truncate([]byte(compileErr.Output))), nil
 	}
 
-	return result, nil
+	return nil, nil
 }
 
-func initIsolate() (string, error) {
-	initCmd := exec.Command(
-		"isolate",
-		"--box-id", "0",
-		// "--cg",
-		"--init",
-	)
-	var initOutput bytes.Buffer
-	var initStderr bytes.Buffer
-	initCmd.Stdout = &initOutput
-	initCmd.Stderr = &initStderr
+func (s *Solver) acquireWorkspace(ctx context.Context, pattern string) (*workspace, error) {
+	boxID, err := s.boxes.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire isolate box: %w", err)
+	}
+
+	if err := os.MkdirAll(s.cfg.WorkDir, 0o755); err != nil {
+		s.boxes.release(boxID)
+		return nil, fmt.Errorf("failed to create work dir: %w", err)
+	}
 
-	if err := initCmd.Run(); err != nil {
-		log.Error().Str("stdout", initOutput.String()).Str("stderr", initStderr.String()).Err(err).Msg("Failed to init isolate")
-		return "", err
+	dir, err := os.MkdirTemp(s.cfg.WorkDir, pattern)
+	if err != nil {
+		s.boxes.release(boxID)
+		return nil, fmt.Errorf("failed to create scratch dir: %w", err)
 	}
 
-	return initOutput.String(), nil
+	return &workspace{
+		BoxID: boxID,
+		Dir:   dir,
+	}, nil
 }
 
-func cleanupIsolate() error {
-	cleanupCmd := exec.Command(
-		"isolate",
-		"--box-id", "0",
-		// "--cg",
-		"--cleanup",
-	)
-	if err := cleanupCmd.Run(); err != nil {
-		log.Error().Err(err).Msg("Failed to cleanup isolate")
-		return fmt.Errorf("failed to cleanup isolate: %w", err)
+func (s *Solver) releaseWorkspace(ws *workspace) {
+	if err := os.RemoveAll(ws.Dir); err != nil {
+		log.Error().Err(err).Str("dir", ws.Dir).Msg("Failed to remove scratch dir")
 	}
 
-	return nil
+	s.boxes.release(ws.BoxID)
 }
 
-func getIsolateConfig(path, language string, limits *dto.Limits) (*runIsolateConfig, error) {
-	cfg := &runIsolateConfig{}
+// getRunConfig copies the artifacts built in dir into the box set up at path
+// and derives its limits.
+func (s *Solver) getRunConfig(boxID int, dir, path string, lang language.Language, limits *dto.Limits) (*runConfig, error) {
+	cfg := &runConfig{
+		Sandbox:     s.sandbox,
+		BoxID:       boxID,
+		Cgroup:      s.cfg.Cgroup,
+		OutputLimit: s.cfg.OutputLimit,
+	}
 
 	boxPath := strings.TrimSpace(path)
 	sandBoxPath := filepath.Join(boxPath, "box")
@@ -229,156 +413,291 @@ func getIsolateConfig(path, language string, limits *dto.Limits) (*runIsolateCon
 	cfg.StderrFile = filepath.Join(sandBoxPath, "stderr.txt")
 	cfg.MetaFile = filepath.Join(boxPath, "meta.txt")
 
-	switch language {
-	case "go":
-		goFile, err := os.ReadFile("solve")
+	for _, artifact := range lang.Artifacts() {
+		data, err := os.ReadFile(filepath.Join(dir, artifact))
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to read go file")
+			log.Error().Err(err).Str("artifact", artifact).Msg("Failed to read artifact")
 			return nil, err
 		}
 
-		goBoxFile := filepath.Join(sandBoxPath, "solve")
-		if err := os.WriteFile(goBoxFile, goFile, 0o755); err != nil {
-			log.Error().Err(err).Msg("Failed to write go file")
+		if err := os.WriteFile(filepath.Join(sandBoxPath, artifact), data, 0o755); err != nil {
+			log.Error().Err(err).Str("artifact", artifact).Msg("Failed to write artifact")
 			return nil, err
 		}
+	}
 
-		cfg.BoxFile = goBoxFile
-		cfg.Time = fmt.Sprintf("%d", limits.TimeLimit)
-		cfg.WallTime = fmt.Sprintf("%d", limits.TimeLimit)
-		cfg.Mem = fmt.Sprintf("%d", 4*1024*1024+toKiloBytes(limits.MemoryLimit))
-		cfg.Processes = fmt.Sprintf("--processes=%d", 64)
-		cfg.RunCommand = []string{"--run", "--", "./solve"}
-	case "python":
-		pythonFile, err := os.ReadFile("code.py")
-		if err != nil {
-			log.Error().Err(err).Msg("Failed to read python file")
-			return nil, err
-		}
+	cfg.TimeLimit = lang.TimeLimit(limits.TimeLimit)
+	cfg.MemoryLimit = lang.MemoryLimit(limits.MemoryLimit)
+	if cfg.Cgroup {
+		// the control group limits what the whole process tree really uses,
+		// so the address space padding runtimes need is not required
+		cfg.SandboxMemory = cfg.MemoryLimit
+	} else {
+		cfg.SandboxMemory = lang.SandboxMemory(limits.MemoryLimit)
+	}
+	cfg.Processes = lang.Processes()
+	cfg.RunCommand = lang.RunCommand()
 
-		pythonBoxFile := filepath.Join(sandBoxPath, "code.py")
-		if err := os.WriteFile(pythonBoxFile, pythonFile, 0o755); err != nil {
-			log.Error().Err(err).Msg("Failed to write python file")
-			return nil, err
-		}
+	return cfg, nil
+}
 
-		cfg.BoxFile = pythonBoxFile
-		cfg.Time = fmt.Sprintf("%d", limits.TimeLimit)
-		cfg.WallTime = fmt.Sprintf("%d", limits.TimeLimit)
-		cfg.Mem = fmt.Sprintf("%d", 4096*1024+toKiloBytes(limits.MemoryLimit))
-		cfg.RunCommand = []string{"--run", "--", "/usr/bin/python3", "./code.py"}
-	default:
-		return nil, fmt.Errorf("unsupported language")
+// answerFileName is the file in the box the harnesses write the
+// returned value to, and the one of gotest submissions the report on the
+// hidden tests, so that whatever the submission prints does not end up in it.
+const answerFileName = "answer.json"
+
+func setAnswerFile(cfg *runConfig, ioMode string) {
+	if model.Harnessed(ioMode) || ioMode == model.IOModeGoTest {
+		cfg.AnswerFile = filepath.Join(filepath.Dir(cfg.StdinFile), answerFileName)
 	}
+}
 
-	return cfg, nil
+// run runs the configured command in the box on the files of its standard
+// streams, or on stdin when given, appending args to it, and returns what the
+// sandbox recorded.
+func run(cfg *runConfig, stdin io.Reader, args ...string) map[string]string {
+	process, err := cfg.Sandbox.Start(cfg, stdin, nil, args...)
+	if err == nil {
+		err = process.Wait()
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to run in sandbox")
+	}
+
+	return cfg.Sandbox.Meta(cfg)
 }
 
-func runIsolate(cfg *runIsolateConfig, test *testDTO.TestCase, result *dto.Result, limits *dto.Limits) error {
-	if err := os.WriteFile(cfg.StdinFile, test.Input, 0o644); err != nil {
+// execution is what a single sandboxed run of the submission produced.
+type execution struct {
+	// Answer is what the output is checked on: the answer file of function
+	// submissions, stdout of whole programs.
+	Answer      []byte
+	Stdout      []byte
+	Stderr      []byte
+	ExitCode    int
+	Duration    time.Duration
+	MemoryUsage int64
+	// Status is empty when the program finished cleanly within its limits.
+	Status       string
+	ErrorMessage *string
+}
+
+// execute runs the configured command in the box on input, appending args to it.
+func execute(cfg *runConfig, input []byte, args ...string) (*execution, error) {
+	if err := os.WriteFile(cfg.StdinFile, input, 0o644); err != nil {
 		log.Error().Err(err).Msg("Failed to write stdin")
-		return err
+		return nil, err
 	}
 
-	args := []string{
-		"--box-id", "0",
-		// "--cg",
-		"--meta", cfg.MetaFile,
-		"--stdin", "stdin.txt",
-		"--stdout", "stdout.txt",
-		"--stderr", "stderr.txt",
-		"--time", cfg.Time,
-		"--wall-time", cfg.WallTime,
-		"--mem", cfg.Mem,
-	}
-	if cfg.Processes != "" {
-		args = append(args, cfg.Processes)
+	return executeFrom(cfg, nil, args...)
+}
+
+// executeFrom runs the configured command in the box like execute, with
+// stdin piped from the given reader when there is one. Unlike the stdin file,
+// what the program reads of the pipe can not be read again.
+func executeFrom(cfg *runConfig, stdin io.Reader, args ...string) (*execution, error) {
+	// the box is reused between tests, an answer left behind must not count
+	if cfg.AnswerFile != "" {
+		if err := os.Remove(cfg.AnswerFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Error().Err(err).Msg("Failed to remove answer file")
+			return nil, err
+		}
 	}
-	args = append(args, cfg.RunCommand...)
-	runCmd := exec.Command("isolate", args...)
 
-	var runStdout bytes.Buffer
-	var runStderr bytes.Buffer
-	runCmd.Stdout = &runStdout
-	runCmd.Stderr = &runStderr
+	metadata := run(cfg, stdin, args...)
+
+	stdoutData, exceeded := readLimited(cfg.StdoutFile, cfg.OutputLimit)
+	stderrData, _ := readLimited(cfg.StderrFile, maxMessageSize+1)
 
-	if err := runCmd.Run(); err != nil {
-		log.Error().Err(err).Str("stdout", runStdout.String()).Str("stderr", runStderr.String()).Msg("Failed to run isolate")
-		// return err
+	answerData := stdoutData
+	if cfg.AnswerFile != "" {
+		var answerExceeded bool
+		answerData, answerExceeded = readLimited(cfg.AnswerFile, cfg.OutputLimit)
+		exceeded = exceeded || answerExceeded
 	}
 
-	stdoutData, _ := os.ReadFile(cfg.StdoutFile)
-	stderrData, _ := os.ReadFile(cfg.StderrFile)
-	metaData, _ := os.ReadFile(cfg.MetaFile)
+	log.Debug().Str("stdout", truncate(stdoutData)).Msg("stdout")
+	log.Debug().Str("stderr", truncate(stderrData)).Msg("stderr")
+	log.Debug().Interface("metadata", metadata).Msg("metadata")
 
-	metadata := parseMetadata(metaData)
+	exec := newExecution(cfg, metadata, stdoutData, stderrData, exceeded)
+	exec.Answer = answerData
 
-	exitCode := 1
-	if exitCodeStr, ok := metadata["exitcode"]; ok {
-		if code, err := strconv.Atoi(exitCodeStr); err == nil {
-			exitCode = code
-		}
+	return exec, nil
+}
+
+// newExecution derives the outcome of a run from its meta file and output.
+// exceeded tells the stdout was cut at the output limit.
+func newExecution(cfg *runConfig, metadata map[string]string, stdoutData, stderrData []byte, exceeded bool) *execution {
+	exitCode := getExitCode(metadata)
+
+	exec := &execution{
+		Stdout:      stdoutData,
+		Stderr:      stderrData,
+		ExitCode:    exitCode,
+		Duration:    getDuration(metadata),
+		MemoryUsage: getMemoryUsage(metadata, cfg.Cgroup),
+	}
+
+	// the kernel stops a program writing past --fsize with SIGXFSZ
+	if exceeded || metadata["exitsig"] == sigxfsz {
+		exec.Status = "OLE"
+		exec.ErrorMessage = utils.Ptr("Output limit exceeded")
+		return exec
 	}
 
-	log.Info().Str("stdout", string(stdoutData)).Msg("stdout")
-	log.Info().Str("stderr", string(stderrData)).Msg("stderr")
-	log.Info().Int("exit_code", exitCode).Msg("exit_code")
-	log.Info().Interface("metadata", metadata).Msg("metadata")
+	// the kernel kills the process tree once the control group hits its limit
+	if cfg.Cgroup && metadata["cg-oom-killed"] != "" {
+		exec.Status = "MLE"
+		exec.ErrorMessage = utils.Ptr("Memory limit exceeded")
+		return exec
+	}
 
-	result.Duration = max(result.Duration, getDuration(metadata))
 	if exitCode != 0 {
 		if status, ok := metadata["status"]; ok {
-			result.Status = status
+			exec.Status = status
 		} else {
-			result.Status = "RE"
+			exec.Status = "RE"
 		}
 
-		if string(stderrData) != "" {
-			result.ErrorMessage = utils.Ptr(string(stderrData))
+		if len(stderrData) != 0 {
+			exec.ErrorMessage = utils.Ptr(truncate(stderrData))
 		} else if message, ok := metadata["message"]; ok {
-			result.ErrorMessage = utils.Ptr(message)
+			exec.ErrorMessage = utils.Ptr(message)
 		} else {
-			result.ErrorMessage = utils.Ptr("Runtime error")
+			exec.ErrorMessage = utils.Ptr("Runtime error")
 		}
 
-		return errNotNilExitCode
+		return exec
 	}
-	result.MemoryUsage = max(result.MemoryUsage, getMemoryUsage(metadata))
 
-	if result.MemoryUsage > limits.MemoryLimit {
-		result.Status = "MLE"
-		result.ErrorMessage = utils.Ptr("Memory limit exceeded")
-		return errWrongAnswer
+	// without a control group max-rss is only known after the run, and only for the largest process
+	if !cfg.Cgroup && exec.MemoryUsage > cfg.MemoryLimit {
+		exec.Status = "MLE"
+		exec.ErrorMessage = utils.Ptr("Memory limit exceeded")
 	}
 
-	if string(stdoutData) != string(test.Output) {
-		result.Status = "WA"
-		result.ErrorMessage = utils.Ptr("Wrong answer")
-		return errWrongAnswer
+	return exec
+}
+
+// stdin is what the program reads for a test input: the JSON object itself
+// for the harnesses, the text it holds for whole programs.
+func stdin(ioMode string, input json.RawMessage) []byte {
+	if !model.Harnessed(ioMode) {
+		return []byte(checker.Text(input))
 	}
 
-	return nil
+	return input
 }
 
-func runTests(path, language string, tests []testDTO.TestCase, result *dto.Result, limits *dto.Limits) error {
-	cfg, err := getIsolateConfig(path, language, limits)
+func runTest(cfg *runConfig, test *testDTO.TestCase, input []byte, chk checker.Checker) (*dto.TestResult, error) {
+	exec, err := execute(cfg, input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.TestResult{
+		Status:      "AC",
+		Duration:    exec.Duration,
+		MemoryUsage: exec.MemoryUsage,
+		ExitCode:    exec.ExitCode,
+		Public:      test.Public,
+	}
+	if test.Public {
+		result.Input = test.Input
+		result.ExpectedOutput = test.Output
+		result.ActualOutput = utils.Ptr(truncate(exec.Answer))
+		if cfg.AnswerFile != "" && len(exec.Stdout) != 0 {
+			result.Stdout = utils.Ptr(truncate(exec.Stdout))
+		}
+	}
+
+	if exec.Status != "" {
+		result.Status = exec.Status
+		result.ErrorMessage = exec.ErrorMessage
+		return result, nil
+	}
+
+	verdict, err := chk.Check(test.Input, test.Output, exec.Answer)
+	var failure *checkerFailure
+	if errors.As(err, &failure) {
+		log.Warn().Err(err).Msg("Checker failed")
+		result.Status = "IE"
+		result.ErrorMessage = utils.Ptr(truncate([]byte(failure.message)))
+		return result, nil
+	}
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get isolate config")
-		return fmt.Errorf("failed to get isolate config: %w", err)
+		log.Error().Err(err).Msg("Failed to check output")
+		return nil, fmt.Errorf("failed to check output: %w", err)
 	}
+
+	if !verdict.OK {
+		result.Status = "WA"
+		result.ErrorMessage = utils.Ptr("Wrong answer")
+		if verdict.Message != "" {
+			result.ErrorMessage = utils.Ptr(verdict.Message)
+		}
+		return result, nil
+	}
+
+	return result, nil
+}
+
+// runTests judges the test cases group by group and scores the attempt by
+// its subtasks. After a failure the remaining tests of the same subtask are
+// skipped, as they can no longer change its score, but the other subtasks
+// are still judged. The status is that of the first failure.
+func (s *Solver) runTests(test *testDTO.Test, judge judgeFunc, result *dto.Result, progress dto.ProgressFunc) error {
 	result.Status = "AC"
+	result.TestResults = make([]dto.TestResult, 0, len(test.Tests))
+
+	groups := test.Groups()
+	failed := make([]bool, len(groups))
+	for i, t := range test.Tests {
+		if failed[t.Subtask] {
+			result.TestResults = append(result.TestResults, dto.TestResult{
+				Index:   i,
+				Subtask: t.Subtask,
+				Status:  "SK",
+				Public:  t.Public,
+			})
+			continue
+		}
+
+		progress(dto.Progress{Stage: "testing", Test: i + 1, Total: len(test.Tests)})
 
-	for _, t := range tests {
-		if err := runIsolate(cfg, &t, result, limits); err != nil {
-			log.Error().Err(err).Msg("Failed to run test")
-			break
+		testResult, err := judge(&t)
+		if err != nil {
+			log.Error().Err(err).Int("test", i).Msg("Failed to run test")
+			return fmt.Errorf("failed to run test %d: %w", i, err)
+		}
+		testResult.Index = i
+		testResult.Subtask = t.Subtask
+		result.TestResults = append(result.TestResults, *testResult)
+
+		result.Duration = max(result.Duration, testResult.Duration)
+		result.MemoryUsage = max(result.MemoryUsage, testResult.MemoryUsage)
+
+		if testResult.Status != "AC" {
+			if result.Status == "AC" {
+				result.Status = testResult.Status
+				result.ErrorMessage = testResult.ErrorMessage
+			}
+			failed[t.Subtask] = true
+		}
+	}
+
+	result.Score = 0
+	for i, group := range groups {
+		if !failed[i] {
+			result.Score += group.Points
 		}
 	}
 
 	return nil
 }
 
-func (s *Solver) renderTemplate(filename string, context map[string]any) error {
+func (s *Solver) renderTemplate(dir string, filename string, context map[string]any) error {
 	template, err := pongo2.FromFile(filename)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get template from file")
@@ -389,6 +708,12 @@ func (s *Solver) renderTemplate(filename string, context map[string]any) error {
 		"code":          context["code"],
 		"function_name": context["function_name"],
 		"parameters":    context["parameters"],
+		"return_type":   context["return_type"],
+		"class_name":    context["class_name"],
+		"constructor":   context["constructor"],
+		"methods":       context["methods"],
+		"tests":         context["tests"],
+		"package":       context["package"],
 	}
 
 	out, err := template.Execute(ctx)
@@ -397,7 +722,7 @@ func (s *Solver) renderTemplate(filename string, context map[string]any) error {
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	if err := os.WriteFile(strings.TrimSuffix(filename, ".j2"), []byte(out), 0o644); err != nil {
+	if err := os.WriteFile(filepath.Join(dir, strings.TrimSuffix(filename, ".j2")), []byte(out), 0o644); err != nil {
 		log.Error().Err(err).Msg("Failed to write file")
 		return fmt.Errorf("failed to write file: %w", err)
 	}
@@ -405,49 +730,6 @@ func (s *Solver) renderTemplate(filename string, context map[string]any) error {
 	return nil
 }
 
-func (s *Solver) compileGolang() (*string, error) {
-	codeGo, _ := os.ReadFile("code.go")
-	harnessGo, _ := os.ReadFile("harness.go")
-
-	codeGoFormatted, err := imports.Process("code.go", codeGo, nil)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to format code.go")
-		return utils.Ptr(err.Error()), fmt.Errorf("failed to format code.go: %w", err)
-	}
-
-	harnessGoFormatted, err := imports.Process("harness.go", harnessGo, nil)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to format harness.go")
-		return nil, fmt.Errorf("failed to format harness.go: %w", err)
-	}
-
-	if err := os.WriteFile("code.go", codeGoFormatted, 0o644); err != nil {
-		log.Error().Err(err).Msg("Failed to write formatted code.go")
-		return nil, fmt.Errorf("failed to write formatted code.go: %w", err)
-	}
-
-	if err := os.WriteFile("harness.go", harnessGoFormatted, 0o644); err != nil {
-		log.Error().Err(err).Msg("Failed to write formatted harness.go")
-		return nil, fmt.Errorf("failed to write formatted harness.go: %w", err)
-	}
-
-	runCmd := exec.Command(
-		"go", "build", "-o", "solve", "code.go", "harness.go",
-	)
-
-	var runStdout bytes.Buffer
-	var runStderr bytes.Buffer
-	runCmd.Stdout = &runStdout
-	runCmd.Stderr = &runStderr
-
-	if err := runCmd.Run(); err != nil {
-		log.Error().Str("stdout", runStdout.String()).Str("stderr", runStderr.String()).Err(err).Msg("Failed to compile golang")
-		return nil, err
-	}
-
-	return nil, nil
-}
-
 func parseMetadata(b []byte) map[string]string {
 	metadata := make(map[string]string)
 	scanner := bufio.NewScanner(strings.NewReader(string(b)))
@@ -474,6 +756,17 @@ func parseTemplateMetadata(metadata json.RawMessage) (map[string]any, error) {
 	return md, nil
 }
 
+func getExitCode(metadata map[string]string) int {
+	exitCode := 1
+	if exitCodeStr, ok := metadata["exitcode"]; ok {
+		if code, err := strconv.Atoi(exitCodeStr); err == nil {
+			exitCode = code
+		}
+	}
+
+	return exitCode
+}
+
 func getDuration(metadata map[string]string) time.Duration {
 	timeStr, ok1 := metadata["time"]
 	timeWallStr, ok2 := metadata["time-wall"]
@@ -504,15 +797,22 @@ func getDuration(metadata map[string]string) time.Duration {
 	return ans
 }
 
-func getMemoryUsage(metadata map[string]string) int64 {
-	memoryStr, ok := metadata["max-rss"]
+// getMemoryUsage returns the peak memory in bytes: of the whole control group
+// when there is one, otherwise of the largest process.
+func getMemoryUsage(metadata map[string]string, cgroup bool) int64 {
+	field := "max-rss"
+	if cgroup {
+		field = "cg-mem"
+	}
+
+	memoryStr, ok := metadata[field]
 	if !ok {
 		return 0
 	}
 
 	memory, err := strconv.ParseInt(memoryStr, 10, 64)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to parse int from max-rss")
+		log.Error().Err(err).Msgf("Failed to parse int from %s", field)
 		return 0
 	}
 
@@ -0,0 +1,302 @@
+package solver
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"problum/internal/checker"
+	"problum/internal/model"
+	"problum/internal/solver/dto"
+	testDTO "problum/internal/test/service/dto"
+)
+
+func TestNewExecution(t *testing.T) {
+	tests := []struct {
+		name     string
+		cgroup   bool
+		metadata map[string]string
+		stderr   string
+		exceeded bool
+		status   string
+		message  string
+	}{
+		{
+			name:     "clean exit",
+			metadata: map[string]string{"exitcode": "0", "time": "0.010", "time-wall": "0.012", "max-rss": "1024"},
+		},
+		{
+			name:     "nonzero exit",
+			metadata: map[string]string{"exitcode": "3", "status": "RE", "message": "Exited with error status 3"},
+			status:   "RE",
+			message:  "Exited with error status 3",
+		},
+		{
+			name:     "nonzero exit with stderr",
+			metadata: map[string]string{"exitcode": "2", "status": "RE", "message": "Exited with error status 2"},
+			stderr:   "panic: boom",
+			status:   "RE",
+			message:  "panic: boom",
+		},
+		{
+			name:     "nonzero exit without status",
+			metadata: map[string]string{"exitcode": "1"},
+			status:   "RE",
+			message:  "Runtime error",
+		},
+		{
+			name:     "time limit",
+			metadata: map[string]string{"status": "TO", "killed": "1", "message": "Time limit exceeded"},
+			status:   "TO",
+			message:  "Time limit exceeded",
+		},
+		{
+			name:     "fatal signal",
+			metadata: map[string]string{"status": "SG", "exitsig": "11", "message": "Caught fatal signal 11"},
+			status:   "SG",
+			message:  "Caught fatal signal 11",
+		},
+		{
+			name:     "output over the limit",
+			metadata: map[string]string{"exitcode": "0"},
+			exceeded: true,
+			status:   "OLE",
+			message:  "Output limit exceeded",
+		},
+		{
+			name:     "file size limit",
+			metadata: map[string]string{"status": "SG", "exitsig": sigxfsz, "message": "Caught fatal signal 25"},
+			status:   "OLE",
+			message:  "Output limit exceeded",
+		},
+		{
+			name:     "control group out of memory",
+			cgroup:   true,
+			metadata: map[string]string{"status": "SG", "exitsig": "9", "cg-oom-killed": "1", "cg-mem": "65536"},
+			status:   "MLE",
+			message:  "Memory limit exceeded",
+		},
+		{
+			name:     "out of memory without control group",
+			metadata: map[string]string{"status": "SG", "exitsig": "9", "cg-oom-killed": "1", "message": "Caught fatal signal 9"},
+			status:   "SG",
+			message:  "Caught fatal signal 9",
+		},
+		{
+			name:     "max-rss over the limit",
+			metadata: map[string]string{"exitcode": "0", "max-rss": "131072"},
+			status:   "MLE",
+			message:  "Memory limit exceeded",
+		},
+		{
+			name:     "max-rss is not the control group memory",
+			cgroup:   true,
+			metadata: map[string]string{"exitcode": "0", "max-rss": "131072", "cg-mem": "1024"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &runConfig{Cgroup: tt.cgroup, MemoryLimit: 64 * 1024 * 1024}
+
+			exec := newExecution(cfg, tt.metadata, nil, []byte(tt.stderr), tt.exceeded)
+			if exec.Status != tt.status {
+				t.Errorf("status = %q, want %q", exec.Status, tt.status)
+			}
+
+			var message string
+			if exec.ErrorMessage != nil {
+				message = *exec.ErrorMessage
+			}
+			if message != tt.message {
+				t.Errorf("message = %q, want %q", message, tt.message)
+			}
+		})
+	}
+}
+
+// localRunConfig runs script with the shell of a LocalSandbox box, with room
+// enough for it unless a test lowers the limits.
+func localRunConfig(t *testing.T, script string) *runConfig {
+	t.Helper()
+
+	sandbox := NewLocalSandbox(t.TempDir())
+	path, err := sandbox.Init(1)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	box := filepath.Join(path, "box")
+
+	return &runConfig{
+		Sandbox:     sandbox,
+		BoxID:       1,
+		StdinFile:   filepath.Join(box, "stdin.txt"),
+		StdoutFile:  filepath.Join(box, "stdout.txt"),
+		StderrFile:  filepath.Join(box, "stderr.txt"),
+		MetaFile:    filepath.Join(path, "meta.txt"),
+		TimeLimit:   time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+		OutputLimit: 1024 * 1024,
+		RunCommand:  []string{"/bin/sh", "-c", script},
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		limit  func(*runConfig)
+		status string
+		score  int
+	}{
+		{
+			name:   "accepted",
+			script: `read a b; echo $((a + b))`,
+			status: "AC",
+			score:  testDTO.DefaultPoints,
+		},
+		{
+			name:   "wrong answer",
+			script: `read a b; echo $((a - b))`,
+			status: "WA",
+		},
+		{
+			name:   "time limit",
+			script: `while :; do :; done`,
+			limit:  func(cfg *runConfig) { cfg.TimeLimit = 200 * time.Millisecond },
+			status: "TO",
+		},
+		{
+			name:   "memory limit",
+			script: `read a b; echo $((a + b))`,
+			limit:  func(cfg *runConfig) { cfg.MemoryLimit = 1024 },
+			status: "MLE",
+		},
+		{
+			name:   "output limit",
+			script: `while :; do echo 3; done`,
+			limit:  func(cfg *runConfig) { cfg.OutputLimit = 1024 },
+			status: "OLE",
+		},
+		{
+			name:   "runtime error",
+			script: `echo 3; exit 3`,
+			status: "RE",
+		},
+	}
+
+	chk, err := checker.New(checker.ModeWhitespace, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := localRunConfig(t, tt.script)
+			if tt.limit != nil {
+				tt.limit(cfg)
+			}
+
+			test := &testDTO.Test{
+				Tests: []testDTO.TestCase{
+					{Input: json.RawMessage(`"1 2\n"`), Output: json.RawMessage(`"3\n"`)},
+					{Input: json.RawMessage(`"2 2\n"`), Output: json.RawMessage(`"4\n"`)},
+				},
+			}
+			judge := func(tc *testDTO.TestCase) (*dto.TestResult, error) {
+				return runTest(cfg, tc, stdin(model.IOModeStdio, tc.Input), chk)
+			}
+
+			result := &dto.Result{}
+			if err := (&Solver{}).runTests(test, judge, result, func(dto.Progress) {}); err != nil {
+				t.Fatalf("runTests failed: %v", err)
+			}
+
+			if result.Status != tt.status || result.Score != tt.score {
+				t.Errorf("status, score = %q, %d, want %q, %d", result.Status, result.Score, tt.status, tt.score)
+			}
+
+			// a failure of the only subtask leaves nothing to win in the rest of it
+			if tt.status != "AC" && result.TestResults[1].Status != "SK" {
+				t.Errorf("test 2 status = %q, want SK", result.TestResults[1].Status)
+			}
+		})
+	}
+}
+
+func TestRunTestsSubtasks(t *testing.T) {
+	// wrong on the second input only
+	cfg := localRunConfig(t, `read a b; if [ "$a" = 2 ]; then echo 0; else echo $((a + b)); fi`)
+
+	chk, err := checker.New(checker.ModeWhitespace, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test := &testDTO.Test{
+		Tests: []testDTO.TestCase{
+			{Input: json.RawMessage(`"1 2"`), Output: json.RawMessage(`"3"`), Subtask: 0},
+			{Input: json.RawMessage(`"2 2"`), Output: json.RawMessage(`"4"`), Subtask: 1},
+			{Input: json.RawMessage(`"3 2"`), Output: json.RawMessage(`"5"`), Subtask: 1},
+			{Input: json.RawMessage(`"4 2"`), Output: json.RawMessage(`"6"`), Subtask: 2},
+		},
+		Subtasks: []testDTO.Subtask{{Points: 20}, {Points: 30}, {Points: 50}},
+	}
+	judge := func(tc *testDTO.TestCase) (*dto.TestResult, error) {
+		return runTest(cfg, tc, stdin(model.IOModeStdio, tc.Input), chk)
+	}
+
+	result := &dto.Result{}
+	if err := (&Solver{}).runTests(test, judge, result, func(dto.Progress) {}); err != nil {
+		t.Fatalf("runTests failed: %v", err)
+	}
+
+	if result.Status != "WA" || result.Score != 70 {
+		t.Errorf("status, score = %q, %d, want WA, 70", result.Status, result.Score)
+	}
+
+	want := []string{"AC", "WA", "SK", "AC"}
+	for i, status := range want {
+		if result.TestResults[i].Status != status {
+			t.Errorf("test %d status = %q, want %q", i+1, result.TestResults[i].Status, status)
+		}
+	}
+}
+
+func TestRunTestCheckerFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		checker string
+		status  string
+	}{
+		{"accepting", `exit 0`, "AC"},
+		{"rejecting", `echo 'too small'; exit 1`, "WA"},
+		{"crashing", `kill -SEGV $$`, "IE"},
+		{"running out of time", `while :; do :; done`, "IE"},
+		{"exiting with a code of its own", `exit 3`, "IE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := localRunConfig(t, `echo 3`)
+
+			checkerCfg := localRunConfig(t, tt.checker)
+			checkerCfg.TimeLimit = 200 * time.Millisecond
+			chk := &customChecker{cfg: checkerCfg}
+
+			tc := &testDTO.TestCase{Input: json.RawMessage(`"1 2\n"`), Output: json.RawMessage(`"3\n"`)}
+			result, err := runTest(cfg, tc, stdin(model.IOModeStdio, tc.Input), chk)
+			if err != nil {
+				t.Fatalf("runTest failed: %v", err)
+			}
+
+			if result.Status != tt.status {
+				t.Errorf("status = %q, want %q", result.Status, tt.status)
+			}
+			if tt.status != "AC" && result.ErrorMessage == nil {
+				t.Errorf("status %s without a message", result.Status)
+			}
+		})
+	}
+}
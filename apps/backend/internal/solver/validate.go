@@ -0,0 +1,130 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"problum/internal/solver/dto"
+	"problum/internal/utils"
+
+	testService "problum/internal/test/service"
+	testDTO "problum/internal/test/service/dto"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Validate judges every reference solution of the problem on its tests, as
+// an attempt in that language would be. A reference solution passes when it
+// is accepted using at most half the time and memory its language is given,
+// so that the limits leave room for solutions a bit slower than the author's,
+// and within the complexity the benchmark of the problem allows.
+func (s *Solver) Validate(ctx context.Context, v *dto.Validate) (*dto.ValidateResult, error) {
+	test, err := s.testSvc.GetByProblemID(ctx, v.ProblemID)
+	if err != nil {
+		if errors.Is(err, testService.ErrNotFound) {
+			return validateFailed("the problem has no tests"), nil
+		}
+
+		log.Error().Err(err).Msg("Failed to get test for problem")
+		return nil, fmt.Errorf("failed to get test for problem: %w", err)
+	}
+
+	benchmark, err := s.getBenchmark(ctx, v.ProblemID)
+	if err != nil {
+		return nil, err
+	}
+
+	languages, err := s.templateSvc.GetLanguagesByProblemID(ctx, v.ProblemID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get languages")
+		return nil, fmt.Errorf("failed to get languages: %w", err)
+	}
+
+	result := &dto.ValidateResult{
+		Status:    "OK",
+		Passed:    true,
+		Languages: make([]dto.ValidatedLanguage, 0, len(languages)),
+	}
+
+	for _, languageName := range languages {
+		template, err := s.templateSvc.GetByProblemIDAndLanguage(ctx, v.ProblemID, languageName)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get template for problem")
+			return nil, fmt.Errorf("failed to get template for problem: %w", err)
+		}
+		if template.Reference == nil {
+			continue
+		}
+
+		validated, err := s.validateReference(ctx, v.ProblemID, languageName, *template.Reference, test, benchmark)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Languages = append(result.Languages, *validated)
+		result.Passed = result.Passed && validated.Passed
+	}
+
+	if len(result.Languages) == 0 {
+		return validateFailed("the problem has no reference solutions"), nil
+	}
+
+	return result, nil
+}
+
+func (s *Solver) validateReference(
+	ctx context.Context,
+	problemID int,
+	languageName, code string,
+	test *testDTO.Test,
+	benchmark *testDTO.Benchmark,
+) (*dto.ValidatedLanguage, error) {
+	sub, err := s.prepare(ctx, problemID, languageName, code)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := s.acquireWorkspace(ctx, fmt.Sprintf("validate-%d-", problemID))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to acquire workspace")
+		return nil, fmt.Errorf("failed to acquire workspace: %w", err)
+	}
+	defer s.releaseWorkspace(ws)
+
+	res, err := s.solve(ctx, ws, sub, test, benchmark, func(dto.Progress) {})
+	if err != nil {
+		return nil, err
+	}
+
+	validated := &dto.ValidatedLanguage{
+		Language:     languageName,
+		Status:       res.Status,
+		ErrorMessage: res.ErrorMessage,
+		Duration:     res.Duration,
+		MemoryUsage:  res.MemoryUsage,
+		TimeBudget:   sub.lang.TimeLimit(sub.limits.TimeLimit) / 2,
+		MemoryBudget: sub.lang.MemoryLimit(sub.limits.MemoryLimit) / 2,
+		Complexity:   res.Complexity,
+	}
+
+	switch {
+	case res.Status != "AC":
+	case validated.Duration > validated.TimeBudget:
+		validated.ErrorMessage = utils.Ptr(fmt.Sprintf("Took %s, more than half the time limit", validated.Duration))
+	case validated.MemoryUsage > validated.MemoryBudget:
+		validated.ErrorMessage = utils.Ptr(fmt.Sprintf("Used %d bytes, more than half the memory limit", validated.MemoryUsage))
+	default:
+		validated.Passed = true
+	}
+
+	return validated, nil
+}
+
+func validateFailed(message string) *dto.ValidateResult {
+	return &dto.ValidateResult{
+		Status:       "OK",
+		ErrorMessage: utils.Ptr(message),
+		Languages:    make([]dto.ValidatedLanguage, 0),
+	}
+}
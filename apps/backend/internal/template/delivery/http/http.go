@@ -0,0 +1,112 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"problum/internal/api"
+	"problum/internal/config"
+	"problum/internal/template/service"
+	"problum/internal/template/service/dto"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog/log"
+)
+
+type Service interface {
+	Create(context.Context, *dto.Template) (*dto.Template, error)
+	Update(context.Context, *dto.Template) (*dto.Template, error)
+	Delete(context.Context, int) error
+}
+
+type Handler struct {
+	cfg *config.Config
+	svc Service
+}
+
+func New(cfg *config.Config, svc Service) *Handler {
+	return &Handler{
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+func (h *Handler) Create(c fiber.Ctx) error {
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	templateReq := &api.TemplateRequest{}
+	if err := c.Bind().JSON(templateReq); err != nil {
+		return err
+	}
+
+	template, err := h.svc.Create(c.Context(), &dto.Template{
+		ProblemID: problemID,
+		Language:  templateReq.Language,
+		Code:      templateReq.Code,
+		Metadata:  templateReq.Metadata,
+		Reference: templateReq.Reference,
+	})
+	if err != nil {
+		return h.fail(c, err, "create template")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.ToAdminAPI(template))
+}
+
+func (h *Handler) Update(c fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("templateID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	templateReq := &api.TemplateRequest{}
+	if err := c.Bind().JSON(templateReq); err != nil {
+		return err
+	}
+
+	template, err := h.svc.Update(c.Context(), &dto.Template{
+		ID:        id,
+		Code:      templateReq.Code,
+		Metadata:  templateReq.Metadata,
+		Reference: templateReq.Reference,
+	})
+	if err != nil {
+		return h.fail(c, err, "update template")
+	}
+
+	return c.JSON(dto.ToAdminAPI(template))
+}
+
+func (h *Handler) Delete(c fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("templateID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if err := h.svc.Delete(c.Context(), id); err != nil {
+		return h.fail(c, err, "delete template")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// fail maps authoring errors to a status code, action is what failed, e.g. "create template".
+func (h *Handler) fail(c fiber.Ctx, err error, action string) error {
+	log.Error().Err(err).Msg("Failed to " + action)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidTemplate):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrExists):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	default:
+		return fmt.Errorf("failed to %s", action)
+	}
+}
@@ -2,14 +2,24 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"problum/internal/database"
 	"problum/internal/model"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/rs/zerolog/log"
 )
 
+const uniqueViolation = "23505"
+
+var (
+	ErrNotFound = errors.New("template not found")
+	ErrExists   = errors.New("template for this language already exists")
+)
+
 type Repository struct {
 	db *database.DB
 }
@@ -20,6 +30,43 @@ func New(db *database.DB) *Repository {
 	}
 }
 
+func (r *Repository) Get(ctx context.Context, id int) (*model.Template, error) {
+	query := `
+	SELECT
+		id,
+		problem_id,
+		language,
+		code,
+		metadata,
+		reference,
+		created_at,
+		updated_at
+	FROM templates
+	WHERE id = $1
+	`
+
+	template := &model.Template{}
+	if err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+		&template.ID,
+		&template.ProblemID,
+		&template.Language,
+		&template.Code,
+		&template.Metadata,
+		&template.Reference,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to get template")
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return template, nil
+}
+
 func (r *Repository) GetByProblemIDAndLanguage(
 	ctx context.Context,
 	problemID int,
@@ -32,6 +79,7 @@ func (r *Repository) GetByProblemIDAndLanguage(
 		language,
 		code,
 		metadata,
+		reference,
 		created_at,
 		updated_at
 	FROM templates
@@ -45,6 +93,7 @@ func (r *Repository) GetByProblemIDAndLanguage(
 		&template.Language,
 		&template.Code,
 		&template.Metadata,
+		&template.Reference,
 		&template.CreatedAt,
 		&template.UpdatedAt,
 	); err != nil {
@@ -89,3 +138,116 @@ func (r *Repository) GetLanguagesByProblemID(ctx context.Context, problemID int)
 
 	return languages, nil
 }
+
+func (r *Repository) Create(ctx context.Context, template *model.Template) (*model.Template, error) {
+	query := `
+	INSERT INTO templates (problem_id, language, code, metadata, reference)
+	SELECT problems.id, $2, $3, $4, $5
+	FROM problems
+	WHERE problems.id = $1
+	RETURNING
+		id,
+		problem_id,
+		language,
+		code,
+		metadata,
+		reference,
+		created_at,
+		updated_at
+	`
+
+	t := &model.Template{}
+	if err := r.db.Pool.QueryRow(ctx, query,
+		template.ProblemID,
+		template.Language,
+		template.Code,
+		template.Metadata,
+		template.Reference,
+	).Scan(
+		&t.ID,
+		&t.ProblemID,
+		&t.Language,
+		&t.Code,
+		&t.Metadata,
+		&t.Reference,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return nil, ErrExists
+		}
+
+		log.Error().Err(err).Msg("Failed to create template")
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return t, nil
+}
+
+// Update replaces the code, metadata and reference solution, the language of a
+// template never changes.
+func (r *Repository) Update(ctx context.Context, template *model.Template) (*model.Template, error) {
+	query := `
+	UPDATE templates
+	SET
+		code = $2,
+		metadata = $3,
+		reference = $4,
+		updated_at = NOW()
+	WHERE id = $1
+	RETURNING
+		id,
+		problem_id,
+		language,
+		code,
+		metadata,
+		reference,
+		created_at,
+		updated_at
+	`
+
+	t := &model.Template{}
+	if err := r.db.Pool.QueryRow(ctx, query, template.ID, template.Code, template.Metadata, template.Reference).Scan(
+		&t.ID,
+		&t.ProblemID,
+		&t.Language,
+		&t.Code,
+		&t.Metadata,
+		&t.Reference,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to update template")
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+
+	return t, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	query := `
+	DELETE FROM templates
+	WHERE id = $1
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete template")
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
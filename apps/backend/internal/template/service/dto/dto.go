@@ -14,10 +14,58 @@ type Template struct {
 	Language  string
 	Code      string
 	Metadata  json.RawMessage
+	// Reference is the author's solution, it is never shown to students.
+	Reference *string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// Metadata describes the function the harnesses call. The Go and Python
+// harnesses build *ListNode, *TreeNode and *GraphNode parameters (ListNode,
+// TreeNode and GraphNode in Python, also within List[...] and Optional[...])
+// from their JSON array form and turn returned ones back into it; matrices are
+// plain nested arrays.
+type Metadata struct {
+	FunctionName string      `json:"function_name"`
+	Parameters   []Parameter `json:"parameters"`
+	ReturnType   string      `json:"return_type,omitempty"`
+}
+
+// DesignMetadata describes the class of a design problem. A test replays
+// {"operations": [...], "arguments": [[...], ...]} on it: an operation named
+// after the class constructs it, any other calls the method of that name, and
+// the answer is the list of what each returned. In Go the constructor is
+// New<ClassName> returning a pointer and methods are capitalized.
+type DesignMetadata struct {
+	ClassName   string   `json:"class_name"`
+	Constructor Method   `json:"constructor"`
+	Methods     []Method `json:"methods"`
+}
+
+type Method struct {
+	Name       string      `json:"name"`
+	Parameters []Parameter `json:"parameters"`
+	ReturnType string      `json:"return_type,omitempty"`
+}
+
+type Parameter struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func ToModel(template *Template) *model.Template {
+	return &model.Template{
+		ID:        template.ID,
+		ProblemID: template.ProblemID,
+		Language:  template.Language,
+		Code:      template.Code,
+		Metadata:  template.Metadata,
+		Reference: template.Reference,
+		CreatedAt: template.CreatedAt,
+		UpdatedAt: template.UpdatedAt,
+	}
+}
+
 func ToDTO(template *model.Template) *Template {
 	return &Template{
 		ID:        template.ID,
@@ -25,11 +73,14 @@ func ToDTO(template *model.Template) *Template {
 		Language:  template.Language,
 		Code:      template.Code,
 		Metadata:  template.Metadata,
+		Reference: template.Reference,
 		CreatedAt: template.CreatedAt,
 		UpdatedAt: template.UpdatedAt,
 	}
 }
 
+// ToAPI leaves the reference solution out, templates are part of the problem
+// students see.
 func ToAPI(template *Template) api.TemplateGetResponse {
 	if template == nil {
 		return api.TemplateGetResponse{}
@@ -45,3 +96,13 @@ func ToAPI(template *Template) api.TemplateGetResponse {
 		UpdatedAt: template.UpdatedAt,
 	}
 }
+
+// ToAdminAPI is ToAPI with the reference solution, for the authors of the problem.
+func ToAdminAPI(template *Template) api.TemplateGetResponse {
+	resp := ToAPI(template)
+	if template != nil {
+		resp.Reference = template.Reference
+	}
+
+	return resp
+}
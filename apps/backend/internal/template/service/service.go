@@ -2,49 +2,404 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
 
 	"problum/internal/model"
+	problemRepository "problum/internal/problem/repository"
+	"problum/internal/signature"
+	"problum/internal/template/repository"
 	"problum/internal/template/service/dto"
 
+	"github.com/bytedance/sonic"
 	"github.com/rs/zerolog/log"
 )
 
+var (
+	ErrInvalidTemplate = errors.New("invalid template")
+	ErrNotFound        = repository.ErrNotFound
+	ErrExists          = repository.ErrExists
+)
+
+// untypedLanguages pass arguments by name and need no parameter types.
+var untypedLanguages = []string{"python"}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 type Repository interface {
+	Get(context.Context, int) (*model.Template, error)
 	GetByProblemIDAndLanguage(context.Context, int, string) (*model.Template, error)
 	GetLanguagesByProblemID(context.Context, int) ([]string, error)
+	Create(context.Context, *model.Template) (*model.Template, error)
+	Update(context.Context, *model.Template) (*model.Template, error)
+	Delete(context.Context, int) error
 }
 
+type ProblemRepository interface {
+	Get(context.Context, int) (*model.Problem, error)
+}
+
+type LanguageRegistry interface {
+	Has(string) bool
+	Filter([]string) []string
+}
+
+// Service keeps templates of function problems against the harnessed
+// languages, those of stdio and interactive problems against the stdio ones,
+// those of gotest problems against the gotest ones and those of design
+// problems against the design ones.
 type Service struct {
-	repo Repository
+	repo      Repository
+	problems  ProblemRepository
+	languages LanguageRegistry
+	stdio     LanguageRegistry
+	goTest    LanguageRegistry
+	design    LanguageRegistry
 }
 
-func New(repo Repository) *Service {
+func New(repo Repository, problems ProblemRepository, languages, stdio, goTest, design LanguageRegistry) *Service {
 	return &Service{
-		repo: repo,
+		repo:      repo,
+		problems:  problems,
+		languages: languages,
+		stdio:     stdio,
+		goTest:    goTest,
+		design:    design,
 	}
 }
 
+func (s *Service) Get(ctx context.Context, id int) (*dto.Template, error) {
+	template, err := s.repo.Get(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get template")
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return dto.ToDTO(template), nil
+}
+
 func (s *Service) GetByProblemIDAndLanguage(
 	ctx context.Context,
 	problemID int,
 	language string,
 ) (*dto.Template, error) {
+	problem, err := s.problems.Get(ctx, problemID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get problem")
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+
+	if !s.registry(problem.IOMode).Has(language) {
+		log.Warn().Str("language", language).Msg("Unsupported language")
+		return nil, fmt.Errorf("unsupported language: %s", language)
+	}
+
+	generated, err := generate(problem, language)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate template")
+		return nil, fmt.Errorf("failed to generate template: %w", err)
+	}
+
 	template, err := s.repo.GetByProblemIDAndLanguage(ctx, problemID, language)
 	if err != nil {
+		if generated != nil && errors.Is(err, ErrNotFound) {
+			return generated, nil
+		}
+
 		log.Error().Err(err).Msg("Failed to get template")
 		return nil, fmt.Errorf("failed to get template: %w", err)
 	}
 
-	return dto.ToDTO(template), nil
+	return merge(dto.ToDTO(template), generated), nil
 }
 
 func (s *Service) GetLanguagesByProblemID(ctx context.Context, problemID int) ([]string, error) {
+	problem, err := s.problems.Get(ctx, problemID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get problem")
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+
 	languages, err := s.repo.GetLanguagesByProblemID(ctx, problemID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get languages")
 		return nil, fmt.Errorf("failed to get languages: %w", err)
 	}
 
-	return languages, nil
+	// a signature makes the problem available in every language expressing it
+	if problem.Signature != nil {
+		for _, language := range signature.Languages() {
+			generated, err := generate(problem, language)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to generate template")
+				return nil, fmt.Errorf("failed to generate template: %w", err)
+			}
+
+			if generated != nil {
+				languages = append(languages, language)
+			}
+		}
+
+		slices.Sort(languages)
+		languages = slices.Compact(languages)
+	}
+
+	return s.registry(problem.IOMode).Filter(languages), nil
+}
+
+func (s *Service) Create(ctx context.Context, template *dto.Template) (*dto.Template, error) {
+	problem, err := s.problems.Get(ctx, template.ProblemID)
+	if err != nil {
+		if errors.Is(err, problemRepository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to get problem")
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+
+	if !s.registry(problem.IOMode).Has(template.Language) {
+		return nil, fmt.Errorf("%w: unsupported language: %s", ErrInvalidTemplate, template.Language)
+	}
+
+	metadata, err := templateMetadata(problem, template.Language, template.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	template.Metadata = metadata
+
+	created, err := s.repo.Create(ctx, dto.ToModel(template))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create template")
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return dto.ToDTO(created), nil
+}
+
+func (s *Service) Update(ctx context.Context, template *dto.Template) (*dto.Template, error) {
+	current, err := s.repo.Get(ctx, template.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get template")
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	problem, err := s.problems.Get(ctx, current.ProblemID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get problem")
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+
+	metadata, err := templateMetadata(problem, current.Language, template.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	template.Metadata = metadata
+
+	updated, err := s.repo.Update(ctx, dto.ToModel(template))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update template")
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+
+	return dto.ToDTO(updated), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		log.Error().Err(err).Msg("Failed to delete template")
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) registry(ioMode string) LanguageRegistry {
+	switch ioMode {
+	case model.IOModeFunction:
+		return s.languages
+	case model.IOModeGoTest:
+		return s.goTest
+	case model.IOModeDesign:
+		return s.design
+	default:
+		return s.stdio
+	}
+}
+
+// generate returns the template the signature of the problem gives the
+// language, nil when the problem has no signature or the language can not
+// express it.
+func generate(problem *model.Problem, language string) (*dto.Template, error) {
+	if problem.Signature == nil {
+		return nil, nil
+	}
+
+	sig, err := signature.Parse(problem.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	generated, err := signature.Generate(sig, language)
+	if errors.Is(err, signature.ErrUnsupported) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := sonic.Marshal(generated.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.Template{
+		ProblemID: problem.ID,
+		Language:  language,
+		Code:      generated.Code,
+		Metadata:  metadata,
+	}, nil
+}
+
+// merge keeps what a stored template adds to the generated one: starter code
+// of its own and the reference solution. The metadata follows the signature.
+func merge(template, generated *dto.Template) *dto.Template {
+	if generated == nil {
+		return template
+	}
+
+	template.Metadata = generated.Metadata
+	if strings.TrimSpace(template.Code) == "" {
+		template.Code = generated.Code
+	}
+
+	return template
+}
+
+// templateMetadata returns the metadata a template is stored with: what the
+// signature of the problem gives the language when it can express it,
+// otherwise the given metadata once it is valid.
+func templateMetadata(problem *model.Problem, language string, raw json.RawMessage) (json.RawMessage, error) {
+	generated, err := generate(problem, language)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate template")
+		return nil, fmt.Errorf("failed to generate template: %w", err)
+	}
+
+	if generated != nil {
+		return generated.Metadata, nil
+	}
+
+	if err := validateMetadata(problem.IOMode, language, raw); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// validateMetadata checks that the metadata renders into a harness that
+// compiles: the harnesses call function_name with the fields of the test input
+// named after the parameters, and a void function is judged by its first parameter.
+// Whole programs have no harness, so any object will do.
+func validateMetadata(ioMode, language string, raw []byte) error {
+	if ioMode == model.IOModeDesign {
+		return validateDesignMetadata(language, raw)
+	}
+
+	metadata := &dto.Metadata{}
+	if err := sonic.Unmarshal(raw, metadata); err != nil {
+		return fmt.Errorf("%w: metadata is not a valid object: %w", ErrInvalidTemplate, err)
+	}
+
+	if ioMode != model.IOModeFunction {
+		return nil
+	}
+
+	if !identifier.MatchString(metadata.FunctionName) {
+		return fmt.Errorf("%w: function_name must be an identifier", ErrInvalidTemplate)
+	}
+
+	if err := validateParameters(language, metadata.Parameters); err != nil {
+		return err
+	}
+
+	if metadata.ReturnType == "void" && len(metadata.Parameters) == 0 {
+		return fmt.Errorf("%w: a void function needs a parameter to return", ErrInvalidTemplate)
+	}
+
+	return nil
+}
+
+// validateDesignMetadata checks the class the design harnesses replay the
+// operations on: operations are told apart by name, so the class and its
+// methods must all be named differently.
+func validateDesignMetadata(language string, raw []byte) error {
+	metadata := &dto.DesignMetadata{}
+	if err := sonic.Unmarshal(raw, metadata); err != nil {
+		return fmt.Errorf("%w: metadata is not a valid object: %w", ErrInvalidTemplate, err)
+	}
+
+	if !identifier.MatchString(metadata.ClassName) {
+		return fmt.Errorf("%w: class_name must be an identifier", ErrInvalidTemplate)
+	}
+
+	if err := validateParameters(language, metadata.Constructor.Parameters); err != nil {
+		return err
+	}
+
+	names := map[string]bool{metadata.ClassName: true}
+	for i, method := range metadata.Methods {
+		if !identifier.MatchString(method.Name) || !unicode.IsLetter(rune(method.Name[0])) {
+			return fmt.Errorf("%w: name of method %d must be an identifier starting with a letter", ErrInvalidTemplate, i)
+		}
+
+		if names[method.Name] {
+			return fmt.Errorf("%w: duplicate operation %s", ErrInvalidTemplate, method.Name)
+		}
+		names[method.Name] = true
+
+		if err := validateParameters(language, method.Parameters); err != nil {
+			return fmt.Errorf("method %s: %w", method.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateParameters checks the parameters are identifiers the harness of the
+// language can name its arguments after, typed unless the language is untyped.
+func validateParameters(language string, parameters []dto.Parameter) error {
+	typed := !slices.Contains(untypedLanguages, language)
+	names := make(map[string]bool, len(parameters))
+
+	for i, param := range parameters {
+		if !identifier.MatchString(param.Name) {
+			return fmt.Errorf("%w: name of parameter %d must be an identifier", ErrInvalidTemplate, i)
+		}
+
+		if typed && strings.TrimSpace(param.Type) == "" {
+			return fmt.Errorf("%w: parameter %s needs a type", ErrInvalidTemplate, param.Name)
+		}
+
+		name := param.Name
+		if language == "go" {
+			// harness.go.j2 names the arguments after the capitalized parameters
+			if !unicode.IsLetter(rune(name[0])) {
+				return fmt.Errorf("%w: parameter %s must start with a letter", ErrInvalidTemplate, param.Name)
+			}
+			name = strings.ToUpper(name[:1]) + name[1:]
+		}
+
+		if names[name] {
+			return fmt.Errorf("%w: duplicate parameter %s", ErrInvalidTemplate, param.Name)
+		}
+		names[name] = true
+	}
+
+	return nil
 }
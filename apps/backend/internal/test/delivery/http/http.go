@@ -0,0 +1,238 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"problum/internal/api"
+	"problum/internal/config"
+	"problum/internal/test/service"
+	"problum/internal/test/service/dto"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog/log"
+)
+
+type Service interface {
+	GetByProblemID(context.Context, int) (*dto.Test, error)
+	Save(context.Context, *dto.Test) (*dto.Test, error)
+	GetGenerator(context.Context, int) (*dto.Generator, error)
+	SaveGenerator(context.Context, *dto.Generator) (*dto.Generator, error)
+	GetBenchmark(context.Context, int) (*dto.Benchmark, error)
+	SaveBenchmark(context.Context, *dto.Benchmark) (*dto.Benchmark, error)
+	DeleteBenchmark(context.Context, int) error
+	Generate(context.Context, *dto.Generation) (*dto.Test, error)
+}
+
+type Handler struct {
+	cfg *config.Config
+	svc Service
+}
+
+func New(cfg *config.Config, svc Service) *Handler {
+	return &Handler{
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// Get returns all tests of the problem, including the hidden ones.
+func (h *Handler) Get(c fiber.Ctx) error {
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	test, err := h.svc.GetByProblemID(c.Context(), problemID)
+	if err != nil {
+		return h.fail(c, err, "get tests")
+	}
+
+	return c.JSON(dto.ToAPI(test))
+}
+
+func (h *Handler) Save(c fiber.Ctx) error {
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	testsReq := &api.TestsRequest{}
+	if err := c.Bind().JSON(testsReq); err != nil {
+		return err
+	}
+
+	tests := make([]dto.TestCase, 0, len(testsReq.Tests))
+	for _, tc := range testsReq.Tests {
+		tests = append(tests, dto.TestCase{
+			Input:   tc.Input,
+			Output:  tc.Output,
+			Public:  tc.Public,
+			Subtask: tc.Subtask,
+		})
+	}
+
+	subtasks := make([]dto.Subtask, 0, len(testsReq.Subtasks))
+	for _, st := range testsReq.Subtasks {
+		subtasks = append(subtasks, dto.Subtask{
+			Name:   st.Name,
+			Points: st.Points,
+		})
+	}
+
+	test, err := h.svc.Save(c.Context(), &dto.Test{
+		ProblemID: problemID,
+		Tests:     tests,
+		Checker: dto.Checker{
+			Mode:     testsReq.Checker.Mode,
+			Epsilon:  testsReq.Checker.Epsilon,
+			Language: testsReq.Checker.Language,
+			Code:     testsReq.Checker.Code,
+		},
+		Subtasks: subtasks,
+	})
+	if err != nil {
+		return h.fail(c, err, "save tests")
+	}
+
+	return c.JSON(dto.ToAPI(test))
+}
+
+func (h *Handler) GetGenerator(c fiber.Ctx) error {
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	generator, err := h.svc.GetGenerator(c.Context(), problemID)
+	if err != nil {
+		return h.fail(c, err, "get generator")
+	}
+
+	return c.JSON(dto.GeneratorToAPI(generator))
+}
+
+func (h *Handler) SaveGenerator(c fiber.Ctx) error {
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	generatorReq := &api.TestGeneratorRequest{}
+	if err := c.Bind().JSON(generatorReq); err != nil {
+		return err
+	}
+
+	generator, err := h.svc.SaveGenerator(c.Context(), &dto.Generator{
+		ProblemID: problemID,
+		Language:  generatorReq.Language,
+		Code:      generatorReq.Code,
+	})
+	if err != nil {
+		return h.fail(c, err, "save generator")
+	}
+
+	return c.JSON(dto.GeneratorToAPI(generator))
+}
+
+func (h *Handler) GetBenchmark(c fiber.Ctx) error {
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	benchmark, err := h.svc.GetBenchmark(c.Context(), problemID)
+	if err != nil {
+		return h.fail(c, err, "get benchmark")
+	}
+
+	return c.JSON(dto.BenchmarkToAPI(benchmark))
+}
+
+func (h *Handler) SaveBenchmark(c fiber.Ctx) error {
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	benchmarkReq := &api.TestBenchmarkRequest{}
+	if err := c.Bind().JSON(benchmarkReq); err != nil {
+		return err
+	}
+
+	inputs := make([]dto.BenchmarkInput, 0, len(benchmarkReq.Inputs))
+	for _, input := range benchmarkReq.Inputs {
+		inputs = append(inputs, dto.BenchmarkInput{
+			Size:  input.Size,
+			Input: input.Input,
+		})
+	}
+
+	benchmark, err := h.svc.SaveBenchmark(c.Context(), &dto.Benchmark{
+		ProblemID:     problemID,
+		Inputs:        inputs,
+		MaxComplexity: benchmarkReq.MaxComplexity,
+	})
+	if err != nil {
+		return h.fail(c, err, "save benchmark")
+	}
+
+	return c.JSON(dto.BenchmarkToAPI(benchmark))
+}
+
+// DeleteBenchmark stops estimating the complexity of attempts on the problem.
+func (h *Handler) DeleteBenchmark(c fiber.Ctx) error {
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if err := h.svc.DeleteBenchmark(c.Context(), problemID); err != nil {
+		return h.fail(c, err, "delete benchmark")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Generate adds generated tests to the problem and returns all of its tests.
+func (h *Handler) Generate(c fiber.Ctx) error {
+	problemID, err := strconv.Atoi(c.Params("problemID"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	generateReq := &api.TestsGenerateRequest{}
+	if err := c.Bind().JSON(generateReq); err != nil {
+		return err
+	}
+
+	test, err := h.svc.Generate(c.Context(), &dto.Generation{
+		ProblemID: problemID,
+		Language:  generateReq.Language,
+		Seeds:     generateReq.Seeds,
+		Subtask:   generateReq.Subtask,
+		Public:    generateReq.Public,
+		Replace:   generateReq.Replace,
+	})
+	if err != nil {
+		return h.fail(c, err, "generate tests")
+	}
+
+	return c.JSON(dto.ToAPI(test))
+}
+
+// fail maps authoring errors to a status code, action is what failed, e.g. "save tests".
+func (h *Handler) fail(c fiber.Ctx, err error, action string) error {
+	log.Error().Err(err).Msg("Failed to " + action)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidTest):
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrGeneratorNotFound), errors.Is(err, service.ErrBenchmarkNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	default:
+		return fmt.Errorf("failed to %s", action)
+	}
+}
@@ -2,14 +2,22 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"problum/internal/database"
 	"problum/internal/model"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog/log"
 )
 
+var (
+	ErrNotFound          = errors.New("tests not found")
+	ErrGeneratorNotFound = errors.New("generator not found")
+	ErrBenchmarkNotFound = errors.New("benchmark not found")
+)
+
 type Repository struct {
 	db *database.DB
 }
@@ -26,6 +34,8 @@ func (r *Repository) GetByProblemID(ctx context.Context, problemID int) (*model.
 		id,
 		problem_id,
 		tests,
+		checker,
+		subtasks,
 		created_at,
 		updated_at
 	FROM tests
@@ -37,12 +47,230 @@ func (r *Repository) GetByProblemID(ctx context.Context, problemID int) (*model.
 		&test.ID,
 		&test.ProblemID,
 		&test.Tests,
+		&test.Checker,
+		&test.Subtasks,
 		&test.CreatedAt,
 		&test.UpdatedAt,
 	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
 		log.Error().Err(err).Msg("Failed to get test by problem id")
 		return nil, fmt.Errorf("failed to get test by problem id")
 	}
 
 	return test, nil
 }
+
+// Save replaces the tests, the checker and the subtasks of a problem.
+func (r *Repository) Save(ctx context.Context, test *model.Test) (*model.Test, error) {
+	query := `
+	INSERT INTO tests (problem_id, tests, checker, subtasks)
+	SELECT problems.id, $2, $3, $4
+	FROM problems
+	WHERE problems.id = $1
+	ON CONFLICT (problem_id) DO UPDATE
+	SET
+		tests = EXCLUDED.tests,
+		checker = EXCLUDED.checker,
+		subtasks = EXCLUDED.subtasks,
+		updated_at = NOW()
+	RETURNING
+		id,
+		problem_id,
+		tests,
+		checker,
+		subtasks,
+		created_at,
+		updated_at
+	`
+
+	t := &model.Test{}
+	if err := r.db.Pool.QueryRow(ctx, query, test.ProblemID, test.Tests, test.Checker, test.Subtasks).Scan(
+		&t.ID,
+		&t.ProblemID,
+		&t.Tests,
+		&t.Checker,
+		&t.Subtasks,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to save tests")
+		return nil, fmt.Errorf("failed to save tests: %w", err)
+	}
+
+	return t, nil
+}
+
+func (r *Repository) GetGenerator(ctx context.Context, problemID int) (*model.Generator, error) {
+	query := `
+	SELECT
+		id,
+		problem_id,
+		language,
+		code,
+		created_at,
+		updated_at
+	FROM generators
+	WHERE problem_id = $1
+	`
+
+	generator := &model.Generator{}
+	if err := r.db.Pool.QueryRow(ctx, query, problemID).Scan(
+		&generator.ID,
+		&generator.ProblemID,
+		&generator.Language,
+		&generator.Code,
+		&generator.CreatedAt,
+		&generator.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrGeneratorNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to get generator")
+		return nil, fmt.Errorf("failed to get generator: %w", err)
+	}
+
+	return generator, nil
+}
+
+// SaveGenerator replaces the test generator of a problem.
+func (r *Repository) SaveGenerator(ctx context.Context, generator *model.Generator) (*model.Generator, error) {
+	query := `
+	INSERT INTO generators (problem_id, language, code)
+	SELECT problems.id, $2, $3
+	FROM problems
+	WHERE problems.id = $1
+	ON CONFLICT (problem_id) DO UPDATE
+	SET
+		language = EXCLUDED.language,
+		code = EXCLUDED.code,
+		updated_at = NOW()
+	RETURNING
+		id,
+		problem_id,
+		language,
+		code,
+		created_at,
+		updated_at
+	`
+
+	g := &model.Generator{}
+	if err := r.db.Pool.QueryRow(ctx, query, generator.ProblemID, generator.Language, generator.Code).Scan(
+		&g.ID,
+		&g.ProblemID,
+		&g.Language,
+		&g.Code,
+		&g.CreatedAt,
+		&g.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to save generator")
+		return nil, fmt.Errorf("failed to save generator: %w", err)
+	}
+
+	return g, nil
+}
+
+func (r *Repository) GetBenchmark(ctx context.Context, problemID int) (*model.Benchmark, error) {
+	query := `
+	SELECT
+		id,
+		problem_id,
+		inputs,
+		max_complexity,
+		created_at,
+		updated_at
+	FROM benchmarks
+	WHERE problem_id = $1
+	`
+
+	benchmark := &model.Benchmark{}
+	if err := r.db.Pool.QueryRow(ctx, query, problemID).Scan(
+		&benchmark.ID,
+		&benchmark.ProblemID,
+		&benchmark.Inputs,
+		&benchmark.MaxComplexity,
+		&benchmark.CreatedAt,
+		&benchmark.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBenchmarkNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to get benchmark")
+		return nil, fmt.Errorf("failed to get benchmark: %w", err)
+	}
+
+	return benchmark, nil
+}
+
+// SaveBenchmark replaces the benchmark of a problem.
+func (r *Repository) SaveBenchmark(ctx context.Context, benchmark *model.Benchmark) (*model.Benchmark, error) {
+	query := `
+	INSERT INTO benchmarks (problem_id, inputs, max_complexity)
+	SELECT problems.id, $2, $3
+	FROM problems
+	WHERE problems.id = $1
+	ON CONFLICT (problem_id) DO UPDATE
+	SET
+		inputs = EXCLUDED.inputs,
+		max_complexity = EXCLUDED.max_complexity,
+		updated_at = NOW()
+	RETURNING
+		id,
+		problem_id,
+		inputs,
+		max_complexity,
+		created_at,
+		updated_at
+	`
+
+	b := &model.Benchmark{}
+	if err := r.db.Pool.QueryRow(ctx, query, benchmark.ProblemID, benchmark.Inputs, benchmark.MaxComplexity).Scan(
+		&b.ID,
+		&b.ProblemID,
+		&b.Inputs,
+		&b.MaxComplexity,
+		&b.CreatedAt,
+		&b.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to save benchmark")
+		return nil, fmt.Errorf("failed to save benchmark: %w", err)
+	}
+
+	return b, nil
+}
+
+// DeleteBenchmark turns the benchmark of a problem off.
+func (r *Repository) DeleteBenchmark(ctx context.Context, problemID int) error {
+	query := `
+	DELETE FROM benchmarks
+	WHERE problem_id = $1
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, problemID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete benchmark")
+		return fmt.Errorf("failed to delete benchmark: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrBenchmarkNotFound
+	}
+
+	return nil
+}
@@ -4,33 +4,291 @@ import (
 	"encoding/json"
 	"time"
 
+	"problum/internal/api"
 	"problum/internal/model"
 
 	"github.com/bytedance/sonic"
 )
 
+// DefaultPoints is what a problem without subtasks is worth.
+const DefaultPoints = 100
+
 type TestCase struct {
-	Input  json.RawMessage `json:"input"`
-	Output json.RawMessage `json:"output"`
+	Input   json.RawMessage `json:"input"`
+	Output  json.RawMessage `json:"output"`
+	Public  bool            `json:"public"`
+	Subtask int             `json:"subtask,omitempty"`
+}
+
+// Subtask is a group of tests. Its points are only awarded when every test
+// of the group passes.
+type Subtask struct {
+	Name   string `json:"name,omitempty"`
+	Points int    `json:"points"`
+}
+
+// Checker selects how the output of a submission is compared with the expected one.
+// Language and Code are only used by the custom mode and the interactor, Code
+// also holds the hidden _test.go file of gotest problems.
+type Checker struct {
+	Mode     string  `json:"mode"`
+	Epsilon  float64 `json:"epsilon,omitempty"`
+	Language string  `json:"language,omitempty"`
+	Code     string  `json:"code,omitempty"`
 }
 
 type Test struct {
 	ID        int
 	ProblemID int
 	Tests     []TestCase `json:"tests"`
+	Checker   Checker    `json:"checker"`
+	Subtasks  []Subtask  `json:"subtasks"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// Groups returns the subtasks tests are scored by. Without any, all tests
+// form a single group worth DefaultPoints.
+func (t *Test) Groups() []Subtask {
+	if len(t.Subtasks) == 0 {
+		return []Subtask{{Points: DefaultPoints}}
+	}
+
+	return t.Subtasks
+}
+
+func (t *Test) MaxScore() int {
+	score := 0
+	for _, group := range t.Groups() {
+		score += group.Points
+	}
+
+	return score
+}
+
 func ToDTO(test *model.Test) *Test {
 	tests := make([]TestCase, 0)
 	sonic.Unmarshal(test.Tests, &tests)
 
+	checker := Checker{}
+	if len(test.Checker) > 0 {
+		sonic.Unmarshal(test.Checker, &checker)
+	}
+	if checker.Mode == "" {
+		checker.Mode = "json"
+	}
+
+	subtasks := make([]Subtask, 0)
+	if len(test.Subtasks) > 0 {
+		sonic.Unmarshal(test.Subtasks, &subtasks)
+	}
+
 	return &Test{
 		ID:        test.ID,
 		ProblemID: test.ProblemID,
 		Tests:     tests,
+		Checker:   checker,
+		Subtasks:  subtasks,
 		CreatedAt: test.CreatedAt,
 		UpdatedAt: test.UpdatedAt,
 	}
 }
+
+func ToModel(test *Test) (*model.Test, error) {
+	tests, err := sonic.Marshal(test.Tests)
+	if err != nil {
+		return nil, err
+	}
+
+	checker, err := sonic.Marshal(test.Checker)
+	if err != nil {
+		return nil, err
+	}
+
+	subtasks, err := sonic.Marshal(test.Subtasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Test{
+		ID:        test.ID,
+		ProblemID: test.ProblemID,
+		Tests:     tests,
+		Checker:   checker,
+		Subtasks:  subtasks,
+		CreatedAt: test.CreatedAt,
+		UpdatedAt: test.UpdatedAt,
+	}, nil
+}
+
+func ToAPI(test *Test) api.TestsGetResponse {
+	tests := make([]api.TestCase, 0, len(test.Tests))
+	for _, tc := range test.Tests {
+		tests = append(tests, api.TestCase{
+			Input:   tc.Input,
+			Output:  tc.Output,
+			Public:  tc.Public,
+			Subtask: tc.Subtask,
+		})
+	}
+
+	subtasks := make([]api.TestSubtask, 0, len(test.Subtasks))
+	for _, st := range test.Subtasks {
+		subtasks = append(subtasks, api.TestSubtask{
+			Name:   st.Name,
+			Points: st.Points,
+		})
+	}
+
+	return api.TestsGetResponse{
+		ProblemID: test.ProblemID,
+		Tests:     tests,
+		Checker: api.TestChecker{
+			Mode:     test.Checker.Mode,
+			Epsilon:  test.Checker.Epsilon,
+			Language: test.Checker.Language,
+			Code:     test.Checker.Code,
+		},
+		Subtasks:  subtasks,
+		MaxScore:  test.MaxScore(),
+		CreatedAt: test.CreatedAt,
+		UpdatedAt: test.UpdatedAt,
+	}
+}
+
+// Generator is an author-provided program written in one of the checker
+// languages. Run as `generator <seed>`, it prints a test input: a JSON object
+// for function problems, the raw text for stdio ones.
+type Generator struct {
+	ID        int
+	ProblemID int
+	Language  string
+	Code      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Generation adds a test for every seed to a problem. The expected outputs
+// come from the reference solution in Language.
+type Generation struct {
+	ProblemID int
+	Language  string
+	Seeds     []int
+	Subtask   int
+	Public    bool
+	Replace   bool
+}
+
+// GenerationResult is what the worker answers a generation with. Status is
+// OK, FAILED when the generator or the reference solution did not work out,
+// or IE.
+type GenerationResult struct {
+	Status       string
+	ErrorMessage *string
+	Tests        []GeneratedTest
+}
+
+type GeneratedTest struct {
+	Seed   int
+	Input  json.RawMessage
+	Output json.RawMessage
+}
+
+// Benchmark is a family of inputs of growing size an accepted submission is
+// timed on to estimate its complexity. An attempt whose complexity exceeds
+// MaxComplexity, when set, is not accepted.
+type Benchmark struct {
+	ID            int
+	ProblemID     int
+	Inputs        []BenchmarkInput
+	MaxComplexity *string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// BenchmarkInput is an input the way tests of the problem hold it, with the
+// n it is of.
+type BenchmarkInput struct {
+	Size  int             `json:"size"`
+	Input json.RawMessage `json:"input"`
+}
+
+func GeneratorToDTO(generator *model.Generator) *Generator {
+	return &Generator{
+		ID:        generator.ID,
+		ProblemID: generator.ProblemID,
+		Language:  generator.Language,
+		Code:      generator.Code,
+		CreatedAt: generator.CreatedAt,
+		UpdatedAt: generator.UpdatedAt,
+	}
+}
+
+func GeneratorToModel(generator *Generator) *model.Generator {
+	return &model.Generator{
+		ID:        generator.ID,
+		ProblemID: generator.ProblemID,
+		Language:  generator.Language,
+		Code:      generator.Code,
+		CreatedAt: generator.CreatedAt,
+		UpdatedAt: generator.UpdatedAt,
+	}
+}
+
+func GeneratorToAPI(generator *Generator) api.TestGeneratorResponse {
+	return api.TestGeneratorResponse{
+		ProblemID: generator.ProblemID,
+		Language:  generator.Language,
+		Code:      generator.Code,
+		CreatedAt: generator.CreatedAt,
+		UpdatedAt: generator.UpdatedAt,
+	}
+}
+
+func BenchmarkToDTO(benchmark *model.Benchmark) *Benchmark {
+	inputs := make([]BenchmarkInput, 0)
+	sonic.Unmarshal(benchmark.Inputs, &inputs)
+
+	return &Benchmark{
+		ID:            benchmark.ID,
+		ProblemID:     benchmark.ProblemID,
+		Inputs:        inputs,
+		MaxComplexity: benchmark.MaxComplexity,
+		CreatedAt:     benchmark.CreatedAt,
+		UpdatedAt:     benchmark.UpdatedAt,
+	}
+}
+
+func BenchmarkToModel(benchmark *Benchmark) (*model.Benchmark, error) {
+	inputs, err := sonic.Marshal(benchmark.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Benchmark{
+		ID:            benchmark.ID,
+		ProblemID:     benchmark.ProblemID,
+		Inputs:        inputs,
+		MaxComplexity: benchmark.MaxComplexity,
+		CreatedAt:     benchmark.CreatedAt,
+		UpdatedAt:     benchmark.UpdatedAt,
+	}, nil
+}
+
+func BenchmarkToAPI(benchmark *Benchmark) api.TestBenchmarkResponse {
+	inputs := make([]api.TestBenchmarkInput, 0, len(benchmark.Inputs))
+	for _, input := range benchmark.Inputs {
+		inputs = append(inputs, api.TestBenchmarkInput{
+			Size:  input.Size,
+			Input: input.Input,
+		})
+	}
+
+	return api.TestBenchmarkResponse{
+		ProblemID:     benchmark.ProblemID,
+		Inputs:        inputs,
+		MaxComplexity: benchmark.MaxComplexity,
+		CreatedAt:     benchmark.CreatedAt,
+		UpdatedAt:     benchmark.UpdatedAt,
+	}
+}
@@ -2,25 +2,89 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
+	"problum/internal/checker"
+	"problum/internal/complexity"
 	"problum/internal/model"
+	problemRepository "problum/internal/problem/repository"
+	"problum/internal/test/repository"
 	"problum/internal/test/service/dto"
 
+	"github.com/bytedance/sonic"
+	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	// maxGeneratedTests caps how many seeds a single generation may run.
+	maxGeneratedTests = 50
+	generateTimeout   = 2 * time.Minute
+	// a benchmark needs a few sizes to tell the classes apart and every input
+	// is run a few times on every accepted attempt
+	minBenchmarkInputs = 4
+	maxBenchmarkInputs = 12
+)
+
+var (
+	ErrInvalidTest       = errors.New("invalid tests")
+	ErrNotFound          = repository.ErrNotFound
+	ErrGeneratorNotFound = repository.ErrGeneratorNotFound
+	ErrBenchmarkNotFound = repository.ErrBenchmarkNotFound
+)
+
+// goTestName matches the top-level test functions go test runs.
+var goTestName = regexp.MustCompile(`^Test[A-Za-z0-9_]*$`)
+
+// stdioCheckers are the checkers that understand plain text output.
+var stdioCheckers = []string{checker.ModeWhitespace, checker.ModeCustom}
+
 type Repository interface {
 	GetByProblemID(ctx context.Context, problemID int) (*model.Test, error)
+	Save(ctx context.Context, test *model.Test) (*model.Test, error)
+	GetGenerator(ctx context.Context, problemID int) (*model.Generator, error)
+	SaveGenerator(ctx context.Context, generator *model.Generator) (*model.Generator, error)
+	GetBenchmark(ctx context.Context, problemID int) (*model.Benchmark, error)
+	SaveBenchmark(ctx context.Context, benchmark *model.Benchmark) (*model.Benchmark, error)
+	DeleteBenchmark(ctx context.Context, problemID int) error
+}
+
+type ProblemRepository interface {
+	Get(context.Context, int) (*model.Problem, error)
+}
+
+// CheckerRegistry holds the languages custom checkers can be written in.
+type CheckerRegistry interface {
+	Has(string) bool
+}
+
+// Requester sends requests to the workers, *nats.Conn is one.
+type Requester interface {
+	RequestWithContext(context.Context, string, []byte) (*nats.Msg, error)
 }
 
 type Service struct {
-	repo Repository
+	repo     Repository
+	problems ProblemRepository
+	checkers CheckerRegistry
+	workers  Requester
 }
 
-func New(repo Repository) *Service {
+func New(repo Repository, problems ProblemRepository, checkers CheckerRegistry, workers Requester) *Service {
 	return &Service{
-		repo: repo,
+		repo:     repo,
+		problems: problems,
+		checkers: checkers,
+		workers:  workers,
 	}
 }
 
@@ -28,8 +92,515 @@ func (s *Service) GetByProblemID(ctx context.Context, problemID int) (*dto.Test,
 	test, err := s.repo.GetByProblemID(ctx, problemID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get test by problem id")
-		return nil, fmt.Errorf("failed to get test by problem id")
+		return nil, fmt.Errorf("failed to get test by problem id: %w", err)
 	}
 
 	return dto.ToDTO(test), nil
 }
+
+// Save replaces all tests of the problem together with its checker.
+func (s *Service) Save(ctx context.Context, test *dto.Test) (*dto.Test, error) {
+	problem, err := s.problems.Get(ctx, test.ProblemID)
+	if err != nil {
+		if errors.Is(err, problemRepository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to get problem")
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+
+	if test.Checker.Mode == "" {
+		switch problem.IOMode {
+		case model.IOModeStdio:
+			test.Checker.Mode = checker.ModeWhitespace
+		case model.IOModeInteractive:
+			test.Checker.Mode = checker.ModeInteractor
+		case model.IOModeGoTest:
+			test.Checker.Mode = checker.ModeGoTest
+		default:
+			test.Checker.Mode = checker.ModeJSON
+		}
+	}
+
+	if err := s.validate(problem.IOMode, test); err != nil {
+		return nil, err
+	}
+
+	m, err := dto.ToModel(test)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to convert tests")
+		return nil, fmt.Errorf("failed to convert tests: %w", err)
+	}
+
+	saved, err := s.repo.Save(ctx, m)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to save tests")
+		return nil, fmt.Errorf("failed to save tests: %w", err)
+	}
+
+	return dto.ToDTO(saved), nil
+}
+
+func (s *Service) GetGenerator(ctx context.Context, problemID int) (*dto.Generator, error) {
+	generator, err := s.repo.GetGenerator(ctx, problemID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get generator")
+		return nil, fmt.Errorf("failed to get generator: %w", err)
+	}
+
+	return dto.GeneratorToDTO(generator), nil
+}
+
+func (s *Service) SaveGenerator(ctx context.Context, generator *dto.Generator) (*dto.Generator, error) {
+	if !s.checkers.Has(generator.Language) {
+		return nil, fmt.Errorf("%w: unsupported generator language: %s", ErrInvalidTest, generator.Language)
+	}
+
+	if strings.TrimSpace(generator.Code) == "" {
+		return nil, fmt.Errorf("%w: generator code is required", ErrInvalidTest)
+	}
+
+	saved, err := s.repo.SaveGenerator(ctx, dto.GeneratorToModel(generator))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to save generator")
+		return nil, fmt.Errorf("failed to save generator: %w", err)
+	}
+
+	return dto.GeneratorToDTO(saved), nil
+}
+
+func (s *Service) GetBenchmark(ctx context.Context, problemID int) (*dto.Benchmark, error) {
+	benchmark, err := s.repo.GetBenchmark(ctx, problemID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get benchmark")
+		return nil, fmt.Errorf("failed to get benchmark: %w", err)
+	}
+
+	return dto.BenchmarkToDTO(benchmark), nil
+}
+
+func (s *Service) SaveBenchmark(ctx context.Context, benchmark *dto.Benchmark) (*dto.Benchmark, error) {
+	problem, err := s.problems.Get(ctx, benchmark.ProblemID)
+	if err != nil {
+		if errors.Is(err, problemRepository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to get problem")
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+
+	if err := validateBenchmark(problem.IOMode, benchmark); err != nil {
+		return nil, err
+	}
+
+	m, err := dto.BenchmarkToModel(benchmark)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to convert benchmark")
+		return nil, fmt.Errorf("failed to convert benchmark: %w", err)
+	}
+
+	saved, err := s.repo.SaveBenchmark(ctx, m)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to save benchmark")
+		return nil, fmt.Errorf("failed to save benchmark: %w", err)
+	}
+
+	return dto.BenchmarkToDTO(saved), nil
+}
+
+func (s *Service) DeleteBenchmark(ctx context.Context, problemID int) error {
+	if err := s.repo.DeleteBenchmark(ctx, problemID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete benchmark")
+		return fmt.Errorf("failed to delete benchmark: %w", err)
+	}
+
+	return nil
+}
+
+// Generate has a worker run the generator of the problem with every seed and
+// answer the inputs with the reference solution, then saves the tests like
+// Save does. The checker and the subtasks are kept.
+func (s *Service) Generate(ctx context.Context, gen *dto.Generation) (*dto.Test, error) {
+	if len(gen.Seeds) == 0 || len(gen.Seeds) > maxGeneratedTests {
+		return nil, fmt.Errorf("%w: expected from 1 to %d seeds", ErrInvalidTest, maxGeneratedTests)
+	}
+
+	problem, err := s.problems.Get(ctx, gen.ProblemID)
+	if err != nil {
+		if errors.Is(err, problemRepository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+
+		log.Error().Err(err).Msg("Failed to get problem")
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+
+	// what an interactive test expects is up to its interactor, not to a solution
+	if problem.IOMode == model.IOModeInteractive {
+		return nil, fmt.Errorf("%w: tests of interactive problems can not be generated", ErrInvalidTest)
+	}
+
+	if problem.IOMode == model.IOModeGoTest {
+		return nil, fmt.Errorf("%w: tests of gotest problems are written by hand", ErrInvalidTest)
+	}
+
+	if _, err := s.repo.GetGenerator(ctx, gen.ProblemID); err != nil {
+		if errors.Is(err, ErrGeneratorNotFound) {
+			return nil, fmt.Errorf("%w: the problem has no generator", ErrInvalidTest)
+		}
+
+		log.Error().Err(err).Msg("Failed to get generator")
+		return nil, fmt.Errorf("failed to get generator: %w", err)
+	}
+
+	test := &dto.Test{ProblemID: gen.ProblemID}
+	current, err := s.repo.GetByProblemID(ctx, gen.ProblemID)
+	if err == nil {
+		test = dto.ToDTO(current)
+	} else if !errors.Is(err, ErrNotFound) {
+		log.Error().Err(err).Msg("Failed to get tests")
+		return nil, fmt.Errorf("failed to get tests: %w", err)
+	}
+
+	result, err := s.requestGeneration(ctx, gen)
+	if err != nil {
+		return nil, err
+	}
+
+	switch result.Status {
+	case "OK":
+	case "FAILED":
+		message := "generation failed"
+		if result.ErrorMessage != nil {
+			message = *result.ErrorMessage
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTest, message)
+	default:
+		return nil, fmt.Errorf("failed to generate tests: worker answered %s", result.Status)
+	}
+
+	if gen.Replace {
+		test.Tests = nil
+	}
+	for _, generated := range result.Tests {
+		test.Tests = append(test.Tests, dto.TestCase{
+			Input:   generated.Input,
+			Output:  generated.Output,
+			Public:  gen.Public,
+			Subtask: gen.Subtask,
+		})
+	}
+
+	return s.Save(ctx, test)
+}
+
+// requestGeneration runs the generation on a worker over a plain NATS request,
+// like custom runs of problems.
+func (s *Service) requestGeneration(ctx context.Context, gen *dto.Generation) (*dto.GenerationResult, error) {
+	payload, err := sonic.Marshal(gen)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal payload")
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, generateTimeout)
+	defer cancel()
+
+	reply, err := s.workers.RequestWithContext(ctx, "TESTS.generate", payload)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to request generation")
+		return nil, fmt.Errorf("failed to request generation: %w", err)
+	}
+
+	result := &dto.GenerationResult{}
+	if err := sonic.Unmarshal(reply.Data, result); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal generation result")
+		return nil, fmt.Errorf("failed to unmarshal generation result: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *Service) validate(ioMode string, test *dto.Test) error {
+	if len(test.Tests) == 0 {
+		return fmt.Errorf("%w: at least one test is required", ErrInvalidTest)
+	}
+
+	switch ioMode {
+	case model.IOModeFunction:
+		if err := validateFunction(test); err != nil {
+			return err
+		}
+	case model.IOModeGoTest:
+		if err := validateGoTest(test); err != nil {
+			return err
+		}
+	case model.IOModeDesign:
+		if err := validateDesign(test); err != nil {
+			return err
+		}
+	default:
+		if err := validateStdio(test); err != nil {
+			return err
+		}
+	}
+
+	if err := validateSubtasks(test); err != nil {
+		return err
+	}
+
+	cfg := test.Checker
+	if cfg.Epsilon < 0 {
+		return fmt.Errorf("%w: checker epsilon must not be negative", ErrInvalidTest)
+	}
+
+	if ioMode == model.IOModeStdio && !slices.Contains(stdioCheckers, cfg.Mode) {
+		return fmt.Errorf("%w: stdio problems are checked by one of %s", ErrInvalidTest, strings.Join(stdioCheckers, ", "))
+	}
+
+	// the interactor is what makes a problem interactive, nothing else can judge it
+	if (ioMode == model.IOModeInteractive) != (cfg.Mode == checker.ModeInteractor) {
+		return fmt.Errorf("%w: interactive problems and only they are judged by an interactor", ErrInvalidTest)
+	}
+
+	if (ioMode == model.IOModeGoTest) != (cfg.Mode == checker.ModeGoTest) {
+		return fmt.Errorf("%w: gotest problems and only they are judged by hidden Go tests", ErrInvalidTest)
+	}
+
+	if cfg.Mode == checker.ModeGoTest {
+		if strings.TrimSpace(cfg.Code) == "" {
+			return fmt.Errorf("%w: hidden Go tests are required", ErrInvalidTest)
+		}
+
+		return nil
+	}
+
+	if cfg.Mode != checker.ModeCustom && cfg.Mode != checker.ModeInteractor {
+		if _, err := checker.New(cfg.Mode, cfg.Epsilon); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidTest, err)
+		}
+
+		return nil
+	}
+
+	if !s.checkers.Has(cfg.Language) {
+		return fmt.Errorf("%w: unsupported checker language: %s", ErrInvalidTest, cfg.Language)
+	}
+
+	if cfg.Code == "" && cfg.Mode == checker.ModeInteractor {
+		return fmt.Errorf("%w: interactor code is required", ErrInvalidTest)
+	}
+
+	if cfg.Code == "" {
+		return fmt.Errorf("%w: custom checker code is required", ErrInvalidTest)
+	}
+
+	return nil
+}
+
+func validateFunction(test *dto.Test) error {
+	for i, tc := range test.Tests {
+		// harnesses pass the fields of the input to the function as named arguments
+		params := map[string]json.RawMessage{}
+		if err := json.Unmarshal(tc.Input, &params); err != nil {
+			return fmt.Errorf("%w: input of test %d must be a JSON object", ErrInvalidTest, i)
+		}
+
+		if !json.Valid(tc.Output) {
+			return fmt.Errorf("%w: output of test %d is not valid JSON", ErrInvalidTest, i)
+		}
+	}
+
+	return nil
+}
+
+// validateStdio checks the tests of whole programs hold raw text: what the
+// program reads and what its output is checked against.
+func validateStdio(test *dto.Test) error {
+	for i, tc := range test.Tests {
+		var text string
+		if err := json.Unmarshal(tc.Input, &text); err != nil {
+			return fmt.Errorf("%w: input of test %d must be a JSON string", ErrInvalidTest, i)
+		}
+
+		if err := json.Unmarshal(tc.Output, &text); err != nil {
+			return fmt.Errorf("%w: output of test %d must be a JSON string", ErrInvalidTest, i)
+		}
+	}
+
+	return nil
+}
+
+// validateGoTest checks the hidden tests parse, leave TestMain to the harness
+// and have a func TestX(t *testing.T) for every test naming one to run.
+// Whether the test passes is the whole verdict, so the output is not used.
+func validateGoTest(test *dto.Test) error {
+	file, err := parser.ParseFile(token.NewFileSet(), "solution_test.go", test.Checker.Code, parser.SkipObjectResolution)
+	if err != nil {
+		return fmt.Errorf("%w: hidden tests do not parse: %s", ErrInvalidTest, err)
+	}
+
+	tests, hasMain := goTests(file)
+	if hasMain {
+		return fmt.Errorf("%w: hidden tests must not declare TestMain", ErrInvalidTest)
+	}
+
+	for i, tc := range test.Tests {
+		var name string
+		if err := json.Unmarshal(tc.Input, &name); err != nil || !goTestName.MatchString(name) {
+			return fmt.Errorf("%w: input of test %d must be the name of a Go test", ErrInvalidTest, i)
+		}
+
+		if !tests[name] {
+			return fmt.Errorf("%w: hidden tests have no func %s(t *testing.T) for test %d", ErrInvalidTest, name, i)
+		}
+	}
+
+	return nil
+}
+
+// goTests returns the top-level functions of file go test runs as tests, and
+// whether it declares TestMain. The testing package may be imported under
+// another name, or not at all when goimports is left to add it.
+func goTests(file *ast.File) (map[string]bool, bool) {
+	testing := "testing"
+	for _, spec := range file.Imports {
+		if spec.Path.Value == `"testing"` && spec.Name != nil {
+			testing = spec.Name.Name
+		}
+	}
+
+	tests := make(map[string]bool)
+	hasMain := false
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil {
+			continue
+		}
+
+		if fn.Name.Name == "TestMain" {
+			hasMain = true
+			continue
+		}
+
+		if !goTestName.MatchString(fn.Name.Name) || fn.Type.TypeParams != nil || fn.Type.Results != nil {
+			continue
+		}
+
+		params := fn.Type.Params.List
+		if len(params) != 1 || len(params[0].Names) > 1 {
+			continue
+		}
+
+		star, ok := params[0].Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := star.X.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "T" {
+			continue
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == testing {
+			tests[fn.Name.Name] = true
+		}
+	}
+
+	return tests, hasMain
+}
+
+// validateDesign checks every test is a sequence of operations with the
+// arguments of each, the first one constructing the class, and expects one
+// output per operation, null for the constructor and void methods.
+func validateDesign(test *dto.Test) error {
+	for i, tc := range test.Tests {
+		var input struct {
+			Operations []string            `json:"operations"`
+			Arguments  [][]json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(tc.Input, &input); err != nil {
+			return fmt.Errorf("%w: input of test %d must be an object of operations and arguments", ErrInvalidTest, i)
+		}
+
+		if len(input.Operations) == 0 {
+			return fmt.Errorf("%w: test %d has no operations", ErrInvalidTest, i)
+		}
+
+		if len(input.Arguments) != len(input.Operations) {
+			return fmt.Errorf("%w: test %d needs the arguments of every operation", ErrInvalidTest, i)
+		}
+
+		var outputs []json.RawMessage
+		if err := json.Unmarshal(tc.Output, &outputs); err != nil {
+			return fmt.Errorf("%w: output of test %d must be a JSON array", ErrInvalidTest, i)
+		}
+
+		if len(outputs) != len(input.Operations) {
+			return fmt.Errorf("%w: output of test %d needs a value for every operation", ErrInvalidTest, i)
+		}
+	}
+
+	return nil
+}
+
+// validateBenchmark checks the inputs are of growing sizes and held the way
+// tests of the problem hold them. Interactive and gotest problems are judged
+// by programs of the author, so there is nothing to time.
+func validateBenchmark(ioMode string, benchmark *dto.Benchmark) error {
+	if ioMode == model.IOModeInteractive || ioMode == model.IOModeGoTest {
+		return fmt.Errorf("%w: %s problems can not be benchmarked", ErrInvalidTest, ioMode)
+	}
+
+	if len(benchmark.Inputs) < minBenchmarkInputs || len(benchmark.Inputs) > maxBenchmarkInputs {
+		return fmt.Errorf("%w: a benchmark needs from %d to %d inputs", ErrInvalidTest, minBenchmarkInputs, maxBenchmarkInputs)
+	}
+
+	for i, input := range benchmark.Inputs {
+		if input.Size < 1 || (i > 0 && input.Size <= benchmark.Inputs[i-1].Size) {
+			return fmt.Errorf("%w: sizes of benchmark inputs must be positive and growing", ErrInvalidTest)
+		}
+
+		var err error
+		if model.Harnessed(ioMode) {
+			err = json.Unmarshal(input.Input, &map[string]json.RawMessage{})
+		} else {
+			err = json.Unmarshal(input.Input, new(string))
+		}
+		if err != nil {
+			return fmt.Errorf("%w: benchmark input %d is not held the way tests of the problem are", ErrInvalidTest, i)
+		}
+	}
+
+	if benchmark.MaxComplexity != nil && !complexity.Valid(*benchmark.MaxComplexity) {
+		return fmt.Errorf("%w: unknown complexity %s", ErrInvalidTest, *benchmark.MaxComplexity)
+	}
+
+	return nil
+}
+
+// validateSubtasks checks every test belongs to an existing subtask and no
+// subtask is left without tests.
+func validateSubtasks(test *dto.Test) error {
+	groups := test.Groups()
+	sizes := make([]int, len(groups))
+
+	for i, tc := range test.Tests {
+		if tc.Subtask < 0 || tc.Subtask >= len(groups) {
+			return fmt.Errorf("%w: test %d refers to unknown subtask %d", ErrInvalidTest, i, tc.Subtask)
+		}
+		sizes[tc.Subtask]++
+	}
+
+	for i, group := range groups {
+		if group.Points < 0 {
+			return fmt.Errorf("%w: points of subtask %d must not be negative", ErrInvalidTest, i)
+		}
+
+		if sizes[i] == 0 {
+			return fmt.Errorf("%w: subtask %d has no tests", ErrInvalidTest, i)
+		}
+	}
+
+	return nil
+}
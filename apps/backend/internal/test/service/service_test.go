@@ -0,0 +1,44 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"problum/internal/test/service/dto"
+)
+
+func TestValidateGoTest(t *testing.T) {
+	tests := []struct {
+		name  string
+		code  string
+		input string
+		ok    bool
+	}{
+		{"test", "package main\n\nfunc TestAdd(t *testing.T) {}", `"TestAdd"`, true},
+		{"testing imported under another name", "package main\n\nimport tt \"testing\"\n\nfunc TestAdd(t *tt.T) {}", `"TestAdd"`, true},
+		{"not a name", "package main\n\nfunc TestAdd(t *testing.T) {}", `1`, false},
+		{"missing test", "package main\n\nfunc TestAdd(t *testing.T) {}", `"TestSub"`, false},
+		{"benchmark", "package main\n\nfunc TestAdd(b *testing.B) {}", `"TestAdd"`, false},
+		{"method", "package main\n\ntype s struct{}\n\nfunc (s) TestAdd(t *testing.T) {}", `"TestAdd"`, false},
+		{"TestMain", "package main\n\nfunc TestMain(m *testing.M) {}\n\nfunc TestAdd(t *testing.T) {}", `"TestAdd"`, false},
+		{"not parsing", "package main\n\nfunc TestAdd(t *testing.T) {", `"TestAdd"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := &dto.Test{
+				Tests:   []dto.TestCase{{Input: json.RawMessage(tt.input)}},
+				Checker: dto.Checker{Code: tt.code},
+			}
+
+			err := validateGoTest(test)
+			if tt.ok && err != nil {
+				t.Errorf("validateGoTest failed: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidTest) {
+				t.Errorf("validateGoTest = %v, want ErrInvalidTest", err)
+			}
+		})
+	}
+}
@@ -30,6 +30,7 @@ func (r *Repository) FindByLogin(ctx context.Context, login string) (*model.User
 		id,
 		login,
 		hashed_password,
+		role,
 		created_at,
 		updated_at
 	FROM users
@@ -41,6 +42,7 @@ func (r *Repository) FindByLogin(ctx context.Context, login string) (*model.User
 		&user.ID,
 		&user.Login,
 		&user.HashedPassword,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	); err != nil {
@@ -64,6 +66,7 @@ func (r *Repository) Create(ctx context.Context, user *model.User) (*model.User,
 		id,
 		login,
 		hashed_password,
+		role,
 		created_at,
 		updated_at 
 	`
@@ -73,6 +76,7 @@ func (r *Repository) Create(ctx context.Context, user *model.User) (*model.User,
 		&u.ID,
 		&u.Login,
 		&u.HashedPassword,
+		&u.Role,
 		&u.CreatedAt,
 		&u.UpdatedAt,
 	); err != nil {
@@ -89,6 +93,7 @@ func (r *Repository) Get(ctx context.Context, userID int) (*model.User, error) {
 		id,
 		login,
 		hashed_password,
+		role,
 		created_at,
 		updated_at
 	FROM users
@@ -100,6 +105,7 @@ func (r *Repository) Get(ctx context.Context, userID int) (*model.User, error) {
 		&user.ID,
 		&user.Login,
 		&user.HashedPassword,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	); err != nil {
@@ -114,3 +120,25 @@ func (r *Repository) Get(ctx context.Context, userID int) (*model.User, error) {
 
 	return user, nil
 }
+
+func (r *Repository) UpdateRole(ctx context.Context, userID int, role string) error {
+	query := `
+	UPDATE users
+	SET
+		role = $2,
+		updated_at = NOW()
+	WHERE id = $1
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, userID, role)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update user role")
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -11,6 +11,7 @@ type User struct {
 	ID             int
 	Login          string
 	HashedPassword string
+	Role           string
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
@@ -20,6 +21,7 @@ func ToModel(user *User) *model.User {
 		ID:             user.ID,
 		Login:          user.Login,
 		HashedPassword: user.HashedPassword,
+		Role:           user.Role,
 		CreatedAt:      user.CreatedAt,
 		UpdatedAt:      user.UpdatedAt,
 	}
@@ -30,6 +32,7 @@ func ToDTO(user *model.User) *User {
 		ID:             user.ID,
 		Login:          user.Login,
 		HashedPassword: user.HashedPassword,
+		Role:           user.Role,
 		CreatedAt:      user.CreatedAt,
 		UpdatedAt:      user.UpdatedAt,
 	}
@@ -39,6 +42,7 @@ func ToAPI(user *User) api.UserGetResponse {
 	return api.UserGetResponse{
 		ID:        user.ID,
 		Login:     user.Login,
+		Role:      user.Role,
 		CreatedAt: user.CreatedAt,
 	}
 }
@@ -14,6 +14,7 @@ type Repository interface {
 	FindByLogin(context.Context, string) (*model.User, error)
 	Create(context.Context, *model.User) (*model.User, error)
 	Get(context.Context, int) (*model.User, error)
+	UpdateRole(context.Context, int, string) error
 }
 
 type Service struct {
@@ -55,3 +56,12 @@ func (s *Service) Create(ctx context.Context, user *dto.User) (*dto.User, error)
 
 	return dto.ToDTO(u), nil
 }
+
+func (s *Service) UpdateRole(ctx context.Context, userID int, role string) error {
+	if err := s.repo.UpdateRole(ctx, userID, role); err != nil {
+		log.Error().Err(err).Msg("Failed to update user role")
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	return nil
+}
@@ -5,15 +5,20 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
 	"problum/internal/config"
 	"problum/internal/database"
+	"problum/internal/language"
 	"problum/internal/nats"
 	"problum/internal/redis"
 	"problum/internal/solver"
+	"problum/internal/utils"
 
+	attemptEvents "problum/internal/attempt/events"
 	attemptRepository "problum/internal/attempt/repository"
 	attemptService "problum/internal/attempt/service"
 	attemptDTO "problum/internal/attempt/service/dto"
@@ -36,13 +41,34 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	inProgressInterval = 5 * time.Second
+	runTimeout         = 30 * time.Second
+	generateTimeout    = 2 * time.Minute
+	validateTimeout    = 5 * time.Minute
+	advisoryTimeout    = 10 * time.Second
+
+	// maxDeliveriesSubject is where JetStream tells that it gave up on
+	// redelivering a message of the worker consumer.
+	maxDeliveriesSubject = "$JS.EVENT.ADVISORY.CONSUMER.MAX_DELIVERIES.ATTEMPTS.worker"
+)
+
 type AttemptService interface {
 	Submit(context.Context, *attemptDTO.Attempt) (int, error)
+	Get(context.Context, int) (*attemptDTO.Attempt, error)
 	Update(ctx context.Context, attempt *attemptDTO.Attempt) error
 }
 
 type Solver interface {
-	Solve(context.Context, *attemptDTO.Attempt) (*solverDTO.Result, error)
+	Solve(context.Context, *attemptDTO.Attempt, solverDTO.ProgressFunc) (*solverDTO.Result, error)
+	Run(context.Context, *solverDTO.Run) (*solverDTO.RunResult, error)
+	Generate(context.Context, *solverDTO.Generate) (*solverDTO.GenerateResult, error)
+	Validate(context.Context, *solverDTO.Validate) (*solverDTO.ValidateResult, error)
+	Warm(context.Context) error
+}
+
+type EventPublisher interface {
+	Publish(*attemptDTO.Event) error
 }
 
 type ProblemService interface {
@@ -60,6 +86,7 @@ type Worker struct {
 	attemptSvc    AttemptService
 	problemSvc    ProblemService
 	solver        Solver
+	events        EventPublisher
 }
 
 func New() (*Worker, error) {
@@ -96,16 +123,47 @@ func New() (*Worker, error) {
 	attemptRepo := attemptRepository.New(db)
 	attemptSvc := attemptService.New(attemptRepo)
 
+	languages, err := language.NewRegistry(cfg.Languages, cfg.Worker.Compile)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create language registry")
+		return nil, fmt.Errorf("failed to create language registry: %w", err)
+	}
+
+	checkers, err := language.NewRegistry(cfg.Checkers, cfg.Worker.Compile)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create checker registry")
+		return nil, fmt.Errorf("failed to create checker registry: %w", err)
+	}
+
+	stdio, err := language.NewRegistry(cfg.Stdio, cfg.Worker.Compile)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create stdio registry")
+		return nil, fmt.Errorf("failed to create stdio registry: %w", err)
+	}
+
+	goTest, err := language.NewRegistry(cfg.GoTest, cfg.Worker.Compile)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create gotest registry")
+		return nil, fmt.Errorf("failed to create gotest registry: %w", err)
+	}
+
+	design, err := language.NewRegistry(cfg.Design, cfg.Worker.Compile)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create design registry")
+		return nil, fmt.Errorf("failed to create design registry: %w", err)
+	}
+
+	problemRepo := problemRepository.New(db)
+
 	templateRepo := templateRepository.New(db)
-	templateSvc := templateService.New(templateRepo)
+	templateSvc := templateService.New(templateRepo, problemRepo, languages, stdio, goTest, design)
 
 	testRepo := testRepository.New(db)
-	testSvc := testService.New(testRepo)
+	testSvc := testService.New(testRepo, problemRepo, checkers, nc)
 
-	problemRepo := problemRepository.New(db)
 	problemSvc := problemService.New(problemRepo, js, attemptSvc, templateSvc)
 
-	solver := solver.New(testSvc, templateSvc, problemSvc)
+	solver := solver.New(cfg.Worker, languages, stdio, goTest, design, checkers, testSvc, templateSvc, problemSvc)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -116,8 +174,11 @@ func New() (*Worker, error) {
 	}
 
 	consumer, err := stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
-		Durable:   "worker",
-		AckPolicy: jetstream.AckExplicitPolicy,
+		Durable:       "worker",
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		FilterSubject: "ATTEMPTS.new",
+		MaxDeliver:    cfg.Worker.MaxDeliver,
+		BackOff:       cfg.Worker.Backoff,
 	})
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create or update consumer for attempts stream")
@@ -134,6 +195,7 @@ func New() (*Worker, error) {
 		attemptStream: stream,
 		consumer:      consumer,
 		solver:        solver,
+		events:        attemptEvents.NewPublisher(nc),
 	}
 
 	return worker, nil
@@ -143,22 +205,52 @@ func (w *Worker) Run() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		w.work(ctx)
 	}()
 
+	requests := &sync.WaitGroup{}
+	subs := make([]*natsgo.Subscription, 0, len(w.handlers()))
+	for subject, handle := range w.handlers() {
+		sub, err := w.serve(ctx, requests, subject, handle)
+		if err != nil {
+			log.Error().Err(err).Str("subject", subject).Msg("Failed to subscribe")
+			cancel()
+			unsubscribe(subs)
+			<-done
+			return fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+		}
+
+		subs = append(subs, sub)
+	}
+
 	ch := make(chan os.Signal, 2)
 	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
 	<-ch
 	log.Info().Msg("Gracefully shutdown server")
 	cancel()
+	unsubscribe(subs)
+	requests.Wait()
+	<-done
 
 	log.Info().Msg("Successfully stopped worker")
 	return nil
 }
 
 func (w *Worker) work(ctx context.Context) {
-	iter, err := w.consumer.Messages()
+	concurrency := max(w.cfg.Worker.Concurrency, 1)
+
+	// a cold cache is better filled once than by the first attempts at once
+	started := time.Now()
+	if err := w.solver.Warm(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to warm languages")
+	} else {
+		log.Info().Dur("duration", time.Since(started)).Msg("Warmed languages")
+	}
+
+	iter, err := w.consumer.Messages(jetstream.PullMaxMessages(concurrency))
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create iterator")
 		return
@@ -170,43 +262,416 @@ func (w *Worker) work(ctx context.Context) {
 		iter.Stop()
 	}()
 
-	log.Info().Msg("Starting pulling messages...")
+	// in-flight attempts are finished even when the worker is stopping
+	handleCtx := context.WithoutCancel(ctx)
+
+	sem := make(chan struct{}, concurrency)
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	log.Info().Int("concurrency", concurrency).Msg("Starting pulling messages...")
 	for {
+		sem <- struct{}{}
+
 		msg, err := iter.Next()
 		if err != nil {
+			<-sem
 			log.Error().Err(err).Msg("Failed to get messages from iterator")
 			break
 		}
 
 		log.Info().Msg("Pulled message")
 
-		message := &attemptDTO.Attempt{}
-		if err := sonic.Unmarshal(msg.Data(), message); err != nil {
-			log.Error().Err(err).Msg("Failed to unmarshal message")
-		} else {
-			log.Info().Interface("message", message).Msg("unmarshaled message")
-		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
 
-		result, err := w.solver.Solve(ctx, message)
-		if err != nil {
-			log.Error().Err(err).Msg("Failed to solve problem")
-			continue
+			w.handle(handleCtx, msg)
+		}()
+	}
+}
+
+func (w *Worker) handle(ctx context.Context, msg jetstream.Msg) {
+	message := &attemptDTO.Attempt{}
+	if err := sonic.Unmarshal(msg.Data(), message); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal message")
+		w.deadLetter(ctx, msg, fmt.Errorf("failed to unmarshal message: %w", err))
+		return
+	}
+	log.Info().Interface("message", message).Msg("unmarshaled message")
+
+	stop := keepInProgress(msg)
+	err := w.judge(ctx, message)
+	stop()
+
+	if err != nil {
+		log.Error().Err(err).Int("attempt_id", message.ID).Msg("Failed to judge attempt")
+		w.retry(ctx, msg, message, err)
+		return
+	}
+
+	if err := msg.Ack(); err != nil {
+		log.Error().Err(err).Msg("Failed to ack message")
+	}
+
+	log.Info().Int("attempt_id", message.ID).Msg("Acked message")
+}
+
+func (w *Worker) judge(ctx context.Context, attempt *attemptDTO.Attempt) error {
+	result, err := w.solver.Solve(ctx, attempt, func(progress solverDTO.Progress) {
+		w.publish(&attemptDTO.Event{
+			Type:      attemptEvents.TypeProgress,
+			AttemptID: attempt.ID,
+			UserID:    attempt.UserID,
+			Stage:     progress.Stage,
+			Test:      progress.Test,
+			Total:     progress.Total,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to solve problem: %w", err)
+	}
+
+	attempt.Duration = result.Duration
+	attempt.MemoryUsage = result.MemoryUsage
+	attempt.Status = result.Status
+	attempt.ErrorMessage = result.ErrorMessage
+	attempt.Score = result.Score
+	attempt.MaxScore = result.MaxScore
+	attempt.Complexity = result.Complexity
+	attempt.CompileDuration = result.CompileDuration
+	attempt.TestResults = toAttemptTestResults(result.TestResults)
+
+	if err := w.attemptSvc.Update(ctx, attempt); err != nil {
+		return fmt.Errorf("failed to update attempt: %w", err)
+	}
+	w.publishVerdict(attempt)
+
+	return nil
+}
+
+// retry hands the message back to JetStream after a backoff delay. Once the
+// deliveries are used up the attempt is marked as an internal error and the
+// message is dead-lettered.
+func (w *Worker) retry(ctx context.Context, msg jetstream.Msg, attempt *attemptDTO.Attempt, cause error) {
+	md, err := msg.Metadata()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get message metadata")
+	} else if int(md.NumDelivered) < w.cfg.Worker.MaxDeliver {
+		delay := w.backoff(md.NumDelivered)
+		if err := msg.NakWithDelay(delay); err != nil {
+			log.Error().Err(err).Msg("Failed to nak message")
 		}
 
-		message.Duration = result.Duration
-		message.MemoryUsage = result.MemoryUsage
-		message.Status = result.Status
-		message.ErrorMessage = result.ErrorMessage
+		log.Warn().
+			Int("attempt_id", attempt.ID).
+			Uint64("delivered", md.NumDelivered).
+			Dur("delay", delay).
+			Msg("Retrying attempt")
+		return
+	}
+
+	w.fail(ctx, attempt)
+	w.deadLetter(ctx, msg, cause)
+}
+
+// fail marks the attempt as an internal error once it is given up on.
+func (w *Worker) fail(ctx context.Context, attempt *attemptDTO.Attempt) {
+	attempt.Status = "IE"
+	attempt.ErrorMessage = utils.Ptr("Internal error")
+	attempt.Duration = 0
+	attempt.MemoryUsage = 0
+	attempt.Score = 0
+	attempt.Complexity = nil
+	attempt.CompileDuration = 0
+	attempt.TestResults = make([]*attemptDTO.TestResult, 0)
+
+	if err := w.attemptSvc.Update(ctx, attempt); err != nil {
+		log.Error().Err(err).Int("attempt_id", attempt.ID).Msg("Failed to mark attempt as internal error")
+	} else {
+		w.publishVerdict(attempt)
+	}
+}
+
+// maxDeliveriesAdvisory is the part of the JetStream advisory the worker needs.
+type maxDeliveriesAdvisory struct {
+	StreamSeq  uint64 `json:"stream_seq"`
+	Deliveries uint64 `json:"deliveries"`
+}
+
+// handleMaxDeliveries fails the attempts JetStream gave up on while no worker
+// saw their last delivery through, like when it crashed or timed out judging
+// them. handle dead-letters the others itself, those never get here.
+func (w *Worker) handleMaxDeliveries(ctx context.Context, msg *natsgo.Msg) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), advisoryTimeout)
+	defer cancel()
+
+	advisory := &maxDeliveriesAdvisory{}
+	if err := sonic.Unmarshal(msg.Data, advisory); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal max deliveries advisory")
+		return
+	}
+
+	stored, err := w.attemptStream.GetMsg(ctx, advisory.StreamSeq)
+	if err != nil {
+		log.Error().Err(err).Uint64("stream_seq", advisory.StreamSeq).Msg("Failed to get undelivered message")
+		return
+	}
+
+	cause := fmt.Errorf("gave up after %d deliveries", advisory.Deliveries)
+
+	message := &attemptDTO.Attempt{}
+	if err := sonic.Unmarshal(stored.Data, message); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal undelivered message")
+	} else if attempt, err := w.attemptSvc.Get(ctx, message.ID); err != nil {
+		log.Error().Err(err).Int("attempt_id", message.ID).Msg("Failed to get undelivered attempt")
+	} else if attempt.Status == "pending" {
+		// a judged attempt only lost its ack, its verdict stands
+		w.fail(ctx, attempt)
+	}
+
+	w.publishDeadLetter(ctx, stored.Subject, stored.Data, advisory.Deliveries, cause)
+}
+
+func (w *Worker) publishVerdict(attempt *attemptDTO.Attempt) {
+	w.publish(&attemptDTO.Event{
+		Type:         attemptEvents.TypeVerdict,
+		AttemptID:    attempt.ID,
+		UserID:       attempt.UserID,
+		Status:       attempt.Status,
+		Duration:     attempt.Duration,
+		MemoryUsage:  attempt.MemoryUsage,
+		ErrorMessage: attempt.ErrorMessage,
+		Score:        attempt.Score,
+		MaxScore:     attempt.MaxScore,
+	})
+}
+
+// publish is best effort: clients fall back to fetching the attempt.
+func (w *Worker) publish(event *attemptDTO.Event) {
+	if err := w.events.Publish(event); err != nil {
+		log.Error().Err(err).Int("attempt_id", event.AttemptID).Msg("Failed to publish attempt event")
+	}
+}
+
+// deadLetter moves a message that can not be judged to ATTEMPTS.dlq.
+func (w *Worker) deadLetter(ctx context.Context, msg jetstream.Msg, cause error) {
+	var delivered uint64
+	if md, err := msg.Metadata(); err == nil {
+		delivered = md.NumDelivered
+	}
+
+	w.publishDeadLetter(ctx, msg.Subject(), msg.Data(), delivered, cause)
+
+	if err := msg.Term(); err != nil {
+		log.Error().Err(err).Msg("Failed to terminate message")
+	}
+}
+
+func (w *Worker) publishDeadLetter(ctx context.Context, subject string, data []byte, delivered uint64, cause error) {
+	dlq := &natsgo.Msg{
+		Subject: "ATTEMPTS.dlq",
+		Data:    data,
+		Header:  natsgo.Header{},
+	}
+	dlq.Header.Set("Problum-Subject", subject)
+	dlq.Header.Set("Problum-Error", cause.Error())
+	if delivered > 0 {
+		dlq.Header.Set("Problum-Delivered", strconv.FormatUint(delivered, 10))
+	}
+
+	if _, err := w.js.PublishMsg(ctx, dlq); err != nil {
+		log.Error().Err(err).Msg("Failed to publish message to dead letter queue")
+	}
 
-		if err := w.attemptSvc.Update(ctx, message); err != nil {
-			log.Error().Err(err).Msg("Failed to update attempt")
-			continue
+	log.Warn().Err(cause).Msg("Dead-lettered message")
+}
+
+func (w *Worker) backoff(delivered uint64) time.Duration {
+	steps := w.cfg.Worker.Backoff
+	if len(steps) == 0 {
+		return 0
+	}
+
+	return steps[min(max(int(delivered), 1), len(steps))-1]
+}
+
+// keepInProgress extends the ack deadline while an attempt is being judged, so
+// that a slow attempt is not redelivered to another worker in the meantime.
+func keepInProgress(msg jetstream.Msg) func() {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(inProgressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := msg.InProgress(); err != nil {
+					log.Error().Err(err).Msg("Failed to mark message in progress")
+				}
+			}
 		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// handlers are the plain requests and advisories the worker answers, by subject.
+func (w *Worker) handlers() map[string]func(context.Context, *natsgo.Msg) {
+	return map[string]func(context.Context, *natsgo.Msg){
+		"RUNS.new":           w.handleRun,
+		"TESTS.generate":     w.handleGenerate,
+		"PROBLEMS.validate":  w.handleValidate,
+		maxDeliveriesSubject: w.handleMaxDeliveries,
+	}
+}
 
-		if err := msg.Ack(); err != nil {
-			log.Error().Err(err).Msg("Failed to ack message")
+func unsubscribe(subs []*natsgo.Subscription) {
+	for _, sub := range subs {
+		if err := sub.Unsubscribe(); err != nil {
+			log.Error().Err(err).Str("subject", sub.Subject).Msg("Failed to unsubscribe")
 		}
+	}
+}
+
+// serve answers requests on subject, such as custom input runs. They are plain
+// NATS requests rather than stream messages: nobody waits for an answer once
+// its request timed out.
+func (w *Worker) serve(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	subject string,
+	handle func(context.Context, *natsgo.Msg),
+) (*natsgo.Subscription, error) {
+	sem := make(chan struct{}, max(w.cfg.Worker.Concurrency, 1))
+
+	return w.nc.QueueSubscribe(subject, "worker", func(msg *natsgo.Msg) {
+		sem <- struct{}{}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			handle(ctx, msg)
+		}()
+	})
+}
 
-		log.Info().Msg("Acked message")
+func (w *Worker) handleRun(ctx context.Context, msg *natsgo.Msg) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), runTimeout)
+	defer cancel()
+
+	result := &solverDTO.RunResult{
+		Status:       "IE",
+		ErrorMessage: utils.Ptr("Internal error"),
+	}
+
+	run := &solverDTO.Run{}
+	if err := sonic.Unmarshal(msg.Data, run); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal run")
+	} else if res, err := w.solver.Run(ctx, run); err != nil {
+		log.Error().Err(err).Int("problem_id", run.ProblemID).Msg("Failed to run")
+	} else {
+		result = res
+	}
+
+	payload, err := sonic.Marshal(result)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal run result")
+		return
+	}
+
+	if err := msg.Respond(payload); err != nil {
+		log.Error().Err(err).Msg("Failed to respond to run")
+	}
+}
+
+// handleGenerate answers a test generation of an author.
+func (w *Worker) handleGenerate(ctx context.Context, msg *natsgo.Msg) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), generateTimeout)
+	defer cancel()
+
+	result := &solverDTO.GenerateResult{
+		Status:       "IE",
+		ErrorMessage: utils.Ptr("Internal error"),
+	}
+
+	gen := &solverDTO.Generate{}
+	if err := sonic.Unmarshal(msg.Data, gen); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal generation")
+	} else if res, err := w.solver.Generate(ctx, gen); err != nil {
+		log.Error().Err(err).Int("problem_id", gen.ProblemID).Msg("Failed to generate tests")
+	} else {
+		result = res
+	}
+
+	payload, err := sonic.Marshal(result)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal generation result")
+		return
+	}
+
+	if err := msg.Respond(payload); err != nil {
+		log.Error().Err(err).Msg("Failed to respond to generation")
+	}
+}
+
+// handleValidate judges the reference solutions of a problem for its author.
+func (w *Worker) handleValidate(ctx context.Context, msg *natsgo.Msg) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), validateTimeout)
+	defer cancel()
+
+	result := &solverDTO.ValidateResult{
+		Status:       "IE",
+		ErrorMessage: utils.Ptr("Internal error"),
+	}
+
+	v := &solverDTO.Validate{}
+	if err := sonic.Unmarshal(msg.Data, v); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal validation")
+	} else if res, err := w.solver.Validate(ctx, v); err != nil {
+		log.Error().Err(err).Int("problem_id", v.ProblemID).Msg("Failed to validate problem")
+	} else {
+		result = res
 	}
+
+	payload, err := sonic.Marshal(result)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal validation result")
+		return
+	}
+
+	if err := msg.Respond(payload); err != nil {
+		log.Error().Err(err).Msg("Failed to respond to validation")
+	}
+}
+
+func toAttemptTestResults(results []solverDTO.TestResult) []*attemptDTO.TestResult {
+	ans := make([]*attemptDTO.TestResult, 0, len(results))
+
+	for _, result := range results {
+		ans = append(ans, &attemptDTO.TestResult{
+			Index:          result.Index,
+			Subtask:        result.Subtask,
+			Status:         result.Status,
+			Duration:       result.Duration,
+			MemoryUsage:    result.MemoryUsage,
+			ExitCode:       result.ExitCode,
+			Public:         result.Public,
+			Input:          result.Input,
+			ExpectedOutput: result.ExpectedOutput,
+			ActualOutput:   result.ActualOutput,
+			Stdout:         result.Stdout,
+		})
+	}
+
+	return ans
 }
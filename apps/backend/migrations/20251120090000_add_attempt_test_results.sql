@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS attempt_test_results (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    attempt_id INTEGER NOT NULL REFERENCES attempts(id) ON DELETE CASCADE,
+    test_index INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    duration INTERVAL,
+    memory_usage BIGINT,
+    exit_code INTEGER,
+    public BOOLEAN DEFAULT false,
+    input JSONB NULL,
+    expected_output JSONB NULL,
+    actual_output TEXT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE(attempt_id, test_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attempt_test_results_attempt_id ON attempt_test_results(attempt_id);
+-- +goose StatementEnd
@@ -0,0 +1,256 @@
+-- +goose Up
+-- +goose StatementBegin
+WITH algo_problems AS (
+    SELECT t.problem_id, t.metadata->>'function_name' AS go_name
+    FROM templates t
+    JOIN problems p ON p.id = t.problem_id
+    JOIN lessons l ON l.id = p.lesson_id
+    JOIN courses c ON c.id = l.course_id
+    WHERE c.name = 'Основы алгоритмов и структур данных' AND t.language = 'go'
+),
+new_templates (go_name, language, code, metadata) AS (
+    VALUES
+    (
+        'Average',
+        'cpp',
+        $$
+// average вычисляет среднее арифметическое элементов вектора.
+double average(const vector<int>& nums) {
+    // Ваша реализация. Помните о преобразовании типов при делении.
+    return 0.0;
+}
+$$,
+        '{"function_name": "average", "parameters": [{"name": "nums", "type": "std::vector<int>"}], "return_type": "double"}'::jsonb
+    ),
+    (
+        'Average',
+        'rust',
+        $$
+// average вычисляет среднее арифметическое элементов вектора.
+fn average(nums: Vec<i32>) -> f64 {
+    // Ваша реализация. Помните о преобразовании типов при делении.
+    0.0
+}
+$$,
+        '{"function_name": "average", "parameters": [{"name": "nums", "type": "Vec<i32>"}], "return_type": "f64"}'::jsonb
+    ),
+    (
+        'GetFirst',
+        'cpp',
+        $$
+// getFirst возвращает первый элемент вектора.
+int getFirst(const vector<int>& nums) {
+    // Ваша реализация
+    return 0;
+}
+$$,
+        '{"function_name": "getFirst", "parameters": [{"name": "nums", "type": "std::vector<int>"}], "return_type": "int"}'::jsonb
+    ),
+    (
+        'GetFirst',
+        'rust',
+        $$
+// get_first возвращает первый элемент вектора.
+fn get_first(nums: Vec<i32>) -> i32 {
+    // Ваша реализация
+    0
+}
+$$,
+        '{"function_name": "get_first", "parameters": [{"name": "nums", "type": "Vec<i32>"}], "return_type": "i32"}'::jsonb
+    ),
+    (
+        'LinearSearch',
+        'cpp',
+        $$
+// linearSearch проверяет, содержится ли target в векторе.
+bool linearSearch(const vector<int>& nums, int target) {
+    // Ваша реализация
+    return false;
+}
+$$,
+        '{"function_name": "linearSearch", "parameters": [{"name": "nums", "type": "std::vector<int>"}, {"name": "target", "type": "int"}], "return_type": "bool"}'::jsonb
+    ),
+    (
+        'LinearSearch',
+        'rust',
+        $$
+// linear_search проверяет, содержится ли target в векторе.
+fn linear_search(nums: Vec<i32>, target: i32) -> bool {
+    // Ваша реализация
+    false
+}
+$$,
+        '{"function_name": "linear_search", "parameters": [{"name": "nums", "type": "Vec<i32>"}, {"name": "target", "type": "i32"}], "return_type": "bool"}'::jsonb
+    ),
+    (
+        'BinarySearch',
+        'cpp',
+        $$
+// binarySearch возвращает индекс target в отсортированном векторе или -1.
+int binarySearch(const vector<int>& nums, int target) {
+    // Ваша реализация
+    return -1;
+}
+$$,
+        '{"function_name": "binarySearch", "parameters": [{"name": "nums", "type": "std::vector<int>"}, {"name": "target", "type": "int"}], "return_type": "int"}'::jsonb
+    ),
+    (
+        'BinarySearch',
+        'rust',
+        $$
+// binary_search возвращает индекс target в отсортированном векторе или -1.
+fn binary_search(nums: Vec<i32>, target: i32) -> i32 {
+    // Ваша реализация
+    -1
+}
+$$,
+        '{"function_name": "binary_search", "parameters": [{"name": "nums", "type": "Vec<i32>"}, {"name": "target", "type": "i32"}], "return_type": "i32"}'::jsonb
+    ),
+    (
+        'BubbleSort',
+        'cpp',
+        $$
+// bubbleSort сортирует вектор целых чисел по возрастанию.
+void bubbleSort(vector<int>& nums) {
+    // Ваша реализация
+}
+$$,
+        '{"function_name": "bubbleSort", "parameters": [{"name": "nums", "type": "std::vector<int>"}], "return_type": "void"}'::jsonb
+    ),
+    (
+        'BubbleSort',
+        'rust',
+        $$
+// bubble_sort сортирует вектор целых чисел по возрастанию.
+fn bubble_sort(nums: &mut Vec<i32>) {
+    // Ваша реализация
+}
+$$,
+        '{"function_name": "bubble_sort", "parameters": [{"name": "nums", "type": "Vec<i32>"}], "return_type": "void"}'::jsonb
+    ),
+    (
+        'Factorial',
+        'cpp',
+        $$
+// factorial вычисляет n! рекурсивно.
+long long factorial(int n) {
+    // Ваша реализация
+    return 0;
+}
+$$,
+        '{"function_name": "factorial", "parameters": [{"name": "n", "type": "int"}], "return_type": "long long"}'::jsonb
+    ),
+    (
+        'Factorial',
+        'rust',
+        $$
+// factorial вычисляет n! рекурсивно.
+fn factorial(n: i32) -> i64 {
+    // Ваша реализация
+    0
+}
+$$,
+        '{"function_name": "factorial", "parameters": [{"name": "n", "type": "i32"}], "return_type": "i64"}'::jsonb
+    ),
+    (
+        'Fibonacci',
+        'cpp',
+        $$
+// fibonacci возвращает n-е число Фибоначчи.
+long long fibonacci(int n) {
+    // Ваша реализация
+    return 0;
+}
+$$,
+        '{"function_name": "fibonacci", "parameters": [{"name": "n", "type": "int"}], "return_type": "long long"}'::jsonb
+    ),
+    (
+        'Fibonacci',
+        'rust',
+        $$
+// fibonacci возвращает n-е число Фибоначчи.
+fn fibonacci(n: i32) -> i64 {
+    // Ваша реализация
+    0
+}
+$$,
+        '{"function_name": "fibonacci", "parameters": [{"name": "n", "type": "i32"}], "return_type": "i64"}'::jsonb
+    ),
+    (
+        'TwoSum',
+        'cpp',
+        $$
+// twoSum возвращает индексы двух чисел, сумма которых равна target.
+vector<int> twoSum(const vector<int>& nums, int target) {
+    // Ваша реализация
+    return {};
+}
+$$,
+        '{"function_name": "twoSum", "parameters": [{"name": "nums", "type": "std::vector<int>"}, {"name": "target", "type": "int"}], "return_type": "std::vector<int>"}'::jsonb
+    ),
+    (
+        'TwoSum',
+        'rust',
+        $$
+// two_sum возвращает индексы двух чисел, сумма которых равна target.
+fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {
+    // Ваша реализация
+    Vec::new()
+}
+$$,
+        '{"function_name": "two_sum", "parameters": [{"name": "nums", "type": "Vec<i32>"}, {"name": "target", "type": "i32"}], "return_type": "Vec<i32>"}'::jsonb
+    ),
+    (
+        'ReverseWords',
+        'cpp',
+        $$
+// reverseWords переставляет слова в строке в обратном порядке.
+string reverseWords(const string& s) {
+    // Ваша реализация
+    return "";
+}
+$$,
+        '{"function_name": "reverseWords", "parameters": [{"name": "s", "type": "std::string"}], "return_type": "std::string"}'::jsonb
+    ),
+    (
+        'ReverseWords',
+        'rust',
+        $$
+// reverse_words переставляет слова в строке в обратном порядке.
+fn reverse_words(s: String) -> String {
+    // Ваша реализация
+    String::new()
+}
+$$,
+        '{"function_name": "reverse_words", "parameters": [{"name": "s", "type": "String"}], "return_type": "String"}'::jsonb
+    ),
+    (
+        'MaxSubarrayLen',
+        'cpp',
+        $$
+// maxSubarrayLen возвращает длину самого длинного подмассива с суммой k.
+int maxSubarrayLen(const vector<int>& nums, int k) {
+    // Ваша реализация
+    return 0;
+}
+$$,
+        '{"function_name": "maxSubarrayLen", "parameters": [{"name": "nums", "type": "std::vector<int>"}, {"name": "k", "type": "int"}], "return_type": "int"}'::jsonb
+    ),
+    (
+        'MaxSubarrayLen',
+        'rust',
+        $$
+// max_subarray_len возвращает длину самого длинного подмассива с суммой k.
+fn max_subarray_len(nums: Vec<i32>, k: i32) -> i32 {
+    // Ваша реализация
+    0
+}
+$$,
+        '{"function_name": "max_subarray_len", "parameters": [{"name": "nums", "type": "Vec<i32>"}, {"name": "k", "type": "i32"}], "return_type": "i32"}'::jsonb
+    )
+)
+INSERT INTO templates (problem_id, language, code, metadata)
+SELECT ap.problem_id, nt.language, nt.code, nt.metadata
+FROM new_templates nt
+JOIN algo_problems ap ON ap.go_name = nt.go_name;
+-- +goose StatementEnd
@@ -0,0 +1,4 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS checker JSONB NOT NULL DEFAULT '{"mode": "json"}'::jsonb;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'student' CHECK (role IN ('student', 'admin'));
+
+ALTER TABLE problems ADD COLUMN IF NOT EXISTS position INTEGER;
+
+UPDATE problems
+SET position = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY lesson_id ORDER BY id) AS position
+    FROM problems
+) AS ordered
+WHERE problems.id = ordered.id;
+
+DROP INDEX IF EXISTS idx_problems_lesson_id;
+
+CREATE INDEX IF NOT EXISTS idx_problems_lesson_id_position ON problems(lesson_id, position);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tests_problem_id ON tests(problem_id);
+
+DROP INDEX IF EXISTS idx_templates_problem_id_language;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_templates_problem_id_language ON templates(problem_id, language);
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('student', 'author', 'admin'));
+
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS owner_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_courses_owner_id ON courses(owner_id);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id_revoked ON user_sessions(user_id, revoked);
+-- +goose StatementEnd
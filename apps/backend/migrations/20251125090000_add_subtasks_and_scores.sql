@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS subtasks JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+ALTER TABLE attempts ADD COLUMN IF NOT EXISTS score INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE attempts ADD COLUMN IF NOT EXISTS max_score INTEGER NOT NULL DEFAULT 0;
+
+UPDATE attempts
+SET
+    score = CASE WHEN status = 'AC' THEN 100 ELSE 0 END,
+    max_score = 100
+WHERE status <> 'pending';
+
+ALTER TABLE attempt_test_results ADD COLUMN IF NOT EXISTS subtask INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
@@ -0,0 +1,8 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE problems ADD COLUMN IF NOT EXISTS io_mode TEXT NOT NULL DEFAULT 'function';
+
+ALTER TABLE problems DROP CONSTRAINT IF EXISTS problems_io_mode_check;
+
+ALTER TABLE problems ADD CONSTRAINT problems_io_mode_check CHECK (io_mode IN ('function', 'stdio'));
+-- +goose StatementEnd
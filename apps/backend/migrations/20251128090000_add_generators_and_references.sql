@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS reference TEXT;
+
+CREATE TABLE IF NOT EXISTS generators (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    problem_id INTEGER NOT NULL UNIQUE REFERENCES problems(id) ON DELETE CASCADE,
+    language TEXT NOT NULL,
+    code TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS problem_validations (
+    problem_id INTEGER PRIMARY KEY REFERENCES problems(id) ON DELETE CASCADE,
+    passed BOOLEAN NOT NULL,
+    time_limit INTERVAL NOT NULL,
+    memory_limit BIGINT NOT NULL,
+    report JSONB NOT NULL DEFAULT '{}'::jsonb,
+    validated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
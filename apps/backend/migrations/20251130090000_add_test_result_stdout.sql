@@ -0,0 +1,4 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE attempt_test_results ADD COLUMN IF NOT EXISTS stdout TEXT NULL;
+-- +goose StatementEnd
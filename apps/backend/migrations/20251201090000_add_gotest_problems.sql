@@ -0,0 +1,6 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE problems DROP CONSTRAINT IF EXISTS problems_io_mode_check;
+
+ALTER TABLE problems ADD CONSTRAINT problems_io_mode_check CHECK (io_mode IN ('function', 'stdio', 'interactive', 'gotest'));
+-- +goose StatementEnd
@@ -0,0 +1,4 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE problems ADD COLUMN IF NOT EXISTS signature JSONB;
+-- +goose StatementEnd
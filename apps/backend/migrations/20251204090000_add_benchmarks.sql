@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS benchmarks (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    problem_id INTEGER NOT NULL UNIQUE REFERENCES problems(id) ON DELETE CASCADE,
+    inputs JSONB NOT NULL,
+    max_complexity TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+ALTER TABLE attempts ADD COLUMN IF NOT EXISTS complexity TEXT;
+-- +goose StatementEnd
@@ -0,0 +1,4 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE attempts ADD COLUMN IF NOT EXISTS compile_duration INTERVAL NOT NULL DEFAULT '0';
+-- +goose StatementEnd
@@ -1,4 +1,18 @@
 import api from './client';
+import { getAccessToken } from '../features/auth/token';
+
+export type APITestResult = {
+  index: number;
+  status: string;
+  duration: number;
+  memory_usage: number;
+  exit_code: number;
+  public: boolean;
+  input?: unknown;
+  expected_output?: unknown;
+  actual_output?: string;
+  stdout?: string;
+};
 
 export type APIAttempt = {
   id: number;
@@ -8,10 +22,13 @@ export type APIAttempt = {
   memory_usage: number;
   language: string;
   code: string;
-  status: 'pending' | 'AC' | 'WA' | 'CE' | 'RE' | 'TLE' | 'MLE' | 'TO' | 'SG' | 'XX';
+  status: 'pending' | 'AC' | 'WA' | 'CE' | 'RE' | 'TLE' | 'MLE' | 'OLE' | 'CLE' | 'TO' | 'SG' | 'XX' | 'IE';
   error_message: string | null;
+  complexity?: string;
+  compile_duration?: number;
   created_at: string;
   updated_at: string;
+  test_results?: APITestResult[];
 };
 
 type SubmitResponse = {
@@ -31,6 +48,38 @@ export async function submitAttempt(
   return resp.data;
 }
 
+export type APIRunOutput = {
+  status: string;
+  stdout: string;
+  answer?: string;
+  stderr: string;
+  duration: number;
+  memory_usage: number;
+  exit_code: number;
+  error_message: string | null;
+};
+
+export type APIRunResult = {
+  status: 'OK' | 'CE' | 'IE';
+  error_message: string | null;
+  outputs: APIRunOutput[];
+};
+
+export async function runCode(
+  courseId: number,
+  problemId: number,
+  language: string,
+  code: string,
+  inputs: unknown[],
+): Promise<APIRunResult> {
+  const resp = await api.post(
+    `/courses/${courseId}/problems/${problemId}/run`,
+    { language, code, inputs },
+    { timeout: 35000 },
+  );
+  return resp.data;
+}
+
 export async function fetchAttemptById(attemptId: number): Promise<APIAttempt> {
   const resp = await api.get(`/attempts/${attemptId}`);
   return resp.data;
@@ -48,3 +97,43 @@ export async function fetchAttemptsForUser(): Promise<APIAttempt[]> {
   const resp = await api.get('/attempts');
   return (resp.data as { attempts: APIAttempt[] }).attempts;
 }
+
+export type APIAttemptEvent = {
+  type: 'progress' | 'verdict';
+  attempt_id: number;
+  stage?: 'compiling' | 'testing' | 'benchmarking';
+  test?: number;
+  total?: number;
+  status?: APIAttempt['status'];
+  duration?: number;
+  memory_usage?: number;
+  error_message?: string | null;
+};
+
+// EventSource can not send headers, so the access token goes in the query string.
+// Events sent while the stream was down are not replayed, onReconnect is
+// called once it is back so that pending attempts can be refetched.
+export function subscribeAttemptEvents(
+  onEvent: (event: APIAttemptEvent) => void,
+  onReconnect?: () => void,
+): () => void {
+  const token = encodeURIComponent(getAccessToken() ?? '');
+  const source = new EventSource(`/api/attempts/events?access_token=${token}`);
+
+  const handler = (e: MessageEvent) => onEvent(JSON.parse(e.data));
+  source.addEventListener('progress', handler);
+  source.addEventListener('verdict', handler);
+
+  let dropped = false;
+  source.addEventListener('error', () => {
+    dropped = true;
+  });
+  source.addEventListener('open', () => {
+    if (dropped) {
+      dropped = false;
+      onReconnect?.();
+    }
+  });
+
+  return () => source.close();
+}
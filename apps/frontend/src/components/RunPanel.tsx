@@ -0,0 +1,83 @@
+import { useState } from 'react'
+import { useMutation } from '@tanstack/react-query'
+import { Play } from 'lucide-react'
+import { runCode } from '../api/attempts'
+import { Button, Card } from './ui'
+import { formatMemory } from '../utils/formatters'
+
+type Props = {
+    courseId: number
+    problemId: number
+    language: string
+    code: string
+}
+
+export default function RunPanel({ courseId, problemId, language, code }: Props) {
+    const [input, setInput] = useState('{}')
+    const [parseError, setParseError] = useState<string | null>(null)
+
+    const runMutation = useMutation({
+        mutationFn: (inputs: unknown[]) => runCode(courseId, problemId, language, code, inputs),
+    })
+
+    const onRun = () => {
+        try {
+            const parsed = JSON.parse(input)
+            setParseError(null)
+            runMutation.mutate([parsed])
+        } catch {
+            setParseError('Входные данные должны быть корректным JSON')
+        }
+    }
+
+    const result = runMutation.data
+    const output = result?.outputs[0]
+
+    return (
+        <Card>
+            <div className="flex items-center justify-between mb-3">
+                <h3 className="font-semibold">Запуск на своих данных</h3>
+                <Button variant="secondary" onClick={onRun} disabled={runMutation.isPending} className="flex items-center gap-2">
+                    <Play className="w-4 h-4" />
+                    {runMutation.isPending ? 'Выполняется...' : 'Запустить'}
+                </Button>
+            </div>
+            <textarea
+                value={input}
+                onChange={(e) => setInput(e.target.value)}
+                rows={3}
+                spellCheck={false}
+                className="w-full bg-secondary text-secondary-foreground text-xs p-3 rounded-md font-mono"
+            />
+            {parseError && <p className="text-sm text-red-600 mt-2">{parseError}</p>}
+            {runMutation.isError && <p className="text-sm text-red-600 mt-2">Не удалось выполнить запуск</p>}
+
+            {result && result.status !== 'OK' && (
+                <pre className="mt-3 bg-secondary text-secondary-foreground text-xs p-3 rounded-md whitespace-pre-wrap font-mono">
+                    {result.error_message}
+                </pre>
+            )}
+
+            {output && (
+                <div className="mt-3 space-y-2 text-sm">
+                    <div className="text-gray-600">
+                        {output.status} · {(output.duration / 1_000_000).toFixed(2)} ms · {formatMemory(output.memory_usage)}
+                    </div>
+                    <pre className="bg-secondary text-secondary-foreground text-xs p-3 rounded-md whitespace-pre-wrap font-mono">
+                        {output.answer || output.stdout || output.error_message}
+                    </pre>
+                    {output.answer && output.stdout && (
+                        <pre className="bg-secondary text-secondary-foreground text-xs p-3 rounded-md whitespace-pre-wrap font-mono">
+                            {output.stdout}
+                        </pre>
+                    )}
+                    {output.stderr && (
+                        <pre className="bg-secondary text-red-600 text-xs p-3 rounded-md whitespace-pre-wrap font-mono">
+                            {output.stderr}
+                        </pre>
+                    )}
+                </div>
+            )}
+        </Card>
+    )
+}
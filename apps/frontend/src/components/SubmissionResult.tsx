@@ -1,12 +1,13 @@
 import React from 'react'
-import type { APIAttempt } from '../api/attempts'
+import type { APIAttempt, APIAttemptEvent } from '../api/attempts'
 import { Card } from './ui'
-import { CheckCircle2, XCircle, AlertTriangle, Loader2, Clock, MemoryStick, Skull } from 'lucide-react'
+import { CheckCircle2, XCircle, AlertTriangle, Loader2, Clock, MemoryStick, Skull, BrainCircuit } from 'lucide-react'
 import { formatMemory } from '../utils/formatters'
 
 type Props = {
     result: APIAttempt | null
     isPending: boolean
+    progress?: APIAttemptEvent | null
 }
 
 const Stat = ({ label, value, icon }: { label: string; value: string | number, icon: React.ReactNode }) => (
@@ -26,15 +27,28 @@ const statusConfig: Record<string, any> = {
     RE: { text: 'Ошибка выполнения', icon: AlertTriangle, color: 'text-red-600' },
     TLE: { text: 'Превышен лимит времени', icon: Clock, color: 'text-red-600' },
     MLE: { text: 'Превышен лимит памяти', icon: MemoryStick, color: 'text-red-600' },
+    OLE: { text: 'Превышен лимит вывода', icon: AlertTriangle, color: 'text-red-600' },
+    CLE: { text: 'Превышена допустимая сложность', icon: BrainCircuit, color: 'text-red-600' },
 
     TO: { text: 'Превышен лимит времени', icon: Clock, color: 'text-red-600' },
     SG: { text: 'Убито сигналом', icon: Skull, color: 'text-red-600' },
     XX: { text: 'Внутренняя ошибка', icon: AlertTriangle, color: 'text-yellow-600' },
+    IE: { text: 'Внутренняя ошибка', icon: AlertTriangle, color: 'text-yellow-600' },
 
     pending: { text: 'В очереди...', icon: Loader2, color: 'text-gray-600' },
 };
 
-export default function SubmissionResult({ result, isPending }: Props) {
+const progressText = (progress: APIAttemptEvent) => {
+    if (progress.stage === 'testing' && progress.test && progress.total) {
+        return `Тест ${progress.test}/${progress.total}`;
+    }
+    if (progress.stage === 'benchmarking' && progress.test && progress.total) {
+        return `Оценка сложности ${progress.test}/${progress.total}`;
+    }
+    return 'Компиляция...';
+};
+
+export default function SubmissionResult({ result, isPending, progress }: Props) {
     if (isPending && !result) {
         return (
             <Card className="flex flex-col items-center justify-center min-h-[150px]">
@@ -65,6 +79,10 @@ export default function SubmissionResult({ result, isPending }: Props) {
                 </h3>
             </div>
 
+            {result.status === 'pending' && progress && (
+                <p className="mt-3 text-sm text-gray-600">{progressText(progress)}</p>
+            )}
+
             {result.status !== 'pending' && (
                 <>
                     <div className="grid grid-cols-2 gap-4 mt-4 border-b pb-4 mb-4">
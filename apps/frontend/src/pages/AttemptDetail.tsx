@@ -3,7 +3,7 @@ import { useParams, Link } from 'react-router-dom';
 import { useQuery } from '@tanstack/react-query';
 import { fetchAttemptById } from '../api/attempts';
 import { Card } from '../components/ui';
-import { CheckCircle2, XCircle, ArrowLeft, Clock, MemoryStick, Code, AlertTriangle } from 'lucide-react';
+import { CheckCircle2, XCircle, ArrowLeft, Clock, MemoryStick, Code, AlertTriangle, TrendingUp, Hammer } from 'lucide-react';
 import { formatMemory } from '../utils/formatters';
 import CodeEditor from '../components/CodeEditor';
 
@@ -23,7 +23,7 @@ export default function AttemptDetail() {
     
     let StatusIcon = isSuccess ? CheckCircle2 : XCircle;
     if (data.status === 'pending') StatusIcon = Clock;
-    if (data.status === 'CE' || data.status === 'XX') StatusIcon = AlertTriangle;
+    if (data.status === 'CE' || data.status === 'XX' || data.status === 'IE') StatusIcon = AlertTriangle;
 
     const statusColor = isSuccess ? 'text-green-600' : 'text-red-600';
     
@@ -34,10 +34,13 @@ export default function AttemptDetail() {
         RE: "Ошибка выполнения",
         TLE: "Превышен лимит времени",
         MLE: "Превышен лимит памяти",
+        OLE: "Превышен лимит вывода",
+        CLE: "Превышена допустимая сложность",
         
         TO: "Превышен лимит времени (Time Limit)",
         SG: "Завершено сигналом (Signal)",
         XX: "Внутренняя ошибка системы",
+        IE: "Внутренняя ошибка системы",
         
         pending: "В очереди"
     };
@@ -82,6 +85,24 @@ export default function AttemptDetail() {
                             <div className="text-sm font-medium">{formatMemory(data.memory_usage)}</div>
                         </div>
                     </div>
+                    {!!data.compile_duration && (
+                        <div className="flex items-center gap-2">
+                            <Hammer className="w-4 h-4 text-gray-500" />
+                            <div>
+                                <div className="text-xs text-gray-500">Компиляция</div>
+                                <div className="text-sm font-medium">{(data.compile_duration / 1_000_000).toFixed(2)} ms</div>
+                            </div>
+                        </div>
+                    )}
+                    {data.complexity && (
+                        <div className="flex items-center gap-2">
+                            <TrendingUp className="w-4 h-4 text-gray-500" />
+                            <div>
+                                <div className="text-xs text-gray-500">Сложность</div>
+                                <div className="text-sm font-medium">{data.complexity}</div>
+                            </div>
+                        </div>
+                    )}
                 </div>
 
                 {data.error_message && (
@@ -2,10 +2,11 @@ import { useEffect, useMemo, useState, useRef } from 'react';
 import { Link, useParams } from 'react-router-dom';
 import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
 import { useProblem, useProblemAttempts, useCourse } from '../features/courses/hooks';
-import { submitAttempt, fetchAttemptById, type APIAttempt } from '../api/attempts';
+import { submitAttempt, fetchAttemptById, subscribeAttemptEvents, type APIAttempt, type APIAttemptEvent } from '../api/attempts';
 import { loadDraft, saveDraft } from '../utils/storage';
 import CodeEditor from '../components/CodeEditor';
 import SubmissionResult from '../components/SubmissionResult';
+import RunPanel from '../components/RunPanel';
 import { Card, Button, Select } from '../components/ui';
 import { Clock, MemoryStick, BrainCircuit, History, CheckCircle2, XCircle, Sparkles, Loader2 } from 'lucide-react';
 import type { editor } from 'monaco-editor';
@@ -108,17 +109,38 @@ export default function Problem() {
         },
     });
 
+    const [progress, setProgress] = useState<APIAttemptEvent | null>(null);
+
     const { data: attemptResult, isFetching: isPolling } = useQuery({
         queryKey: ['attempt', currentAttemptId],
         queryFn: () => fetchAttemptById(currentAttemptId!),
         enabled: !!currentAttemptId,
+        // verdicts are pushed over the event stream, polling is only a fallback
         refetchInterval: (query) => {
             const data = query.state.data;
-            return data?.status === 'pending' ? 1000 : false;
+            return data?.status === 'pending' ? 5000 : false;
         },
         refetchOnWindowFocus: false,
     });
 
+    useEffect(() => {
+        if (!currentAttemptId) return;
+
+        return subscribeAttemptEvents((event) => {
+            if (event.attempt_id !== currentAttemptId) return;
+
+            if (event.type === 'progress') {
+                setProgress(event);
+                return;
+            }
+
+            setProgress(null);
+            queryClient.invalidateQueries({ queryKey: ['attempt', currentAttemptId] });
+        }, () => {
+            queryClient.invalidateQueries({ queryKey: ['attempt', currentAttemptId] });
+        });
+    }, [currentAttemptId, queryClient]);
+
     useEffect(() => {
         if (attemptResult && attemptResult.status !== 'pending') {
             queryClient.invalidateQueries({ queryKey: ['attempts', 'problem', numericProblemId] });
@@ -128,6 +150,7 @@ export default function Problem() {
 
     const onSubmit = () => {
         setCurrentAttemptId(null);
+        setProgress(null);
         submissionMutation.mutate({ language, code });
     };
 
@@ -214,10 +237,17 @@ export default function Problem() {
                         </Button>
                     </div>
                 </Card>
+                <RunPanel
+                    courseId={numericCourseId}
+                    problemId={numericProblemId}
+                    language={language}
+                    code={code}
+                />
                 <div>
                     <SubmissionResult
                         isPending={isSubmitting}
                         result={attemptResult ?? null}
+                        progress={progress}
                     />
                 </div>
             </div>